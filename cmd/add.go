@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
-	"github.com/caezarr-oss/scotter/pkg/config"
 	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/provider"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
 	"github.com/spf13/cobra"
 )
 
@@ -30,23 +32,27 @@ var addCICmd = &cobra.Command{
 		}
 		
 		// Load configuration
-		configManager := config.NewManager(projectPath)
+		configManager := newConfigManager(projectPath)
 		if err := configManager.Load(); err != nil {
 			return fmt.Errorf("unable to load configuration: %w", err)
 		}
 		
-		// Get CI provider
 		pluginLoader := plugin.NewPluginLoader()
 		registerPlugins(pluginLoader)
-		
-		ciProvider, err := pluginLoader.GetCIProvider(providerName)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		// Get CI provider
+		ciProvider, err := provider.CI(pluginLoader, providerName)
 		if err != nil {
 			return fmt.Errorf("CI provider not available: %w", err)
 		}
 		
 		// Check if language is supported by the provider
 		language := configManager.Config.Language
-		projectType := configManager.Config.ProjectType
 		
 		supported := false
 		for _, l := range ciProvider.SupportedLanguages() {
@@ -62,26 +68,22 @@ var addCICmd = &cobra.Command{
 		}
 		
 		// Generate workflows
-		if err := ciProvider.GenerateWorkflows(projectPath, language, projectType, 
-			configManager.Config.ExtraConfig); err != nil {
+		project := newProject(projectPath, configManager.Config)
+		if err := ciProvider.GenerateWorkflows(cmd.Context(), project); err != nil {
 			return fmt.Errorf("failed to generate workflows: %w", err)
 		}
 		
 		// Get the language provider to generate release script (GoReleaser config)
-		langProvider, err := pluginLoader.GetLanguageProvider(language)
+		langProvider, err := provider.Language(pluginLoader, language)
 		if err != nil {
 			return fmt.Errorf("language provider not available: %w", err)
 		}
 		
-		// Generate release script if applicable for this language
-		config := map[string]interface{}{
-			"project_type": projectType,
-		}
-		
-		// Try to generate the release script, but don't fail if it already exists
-		err = langProvider.GenerateReleaseScript(projectPath, config)
-		if err != nil && err.Error() != ".goreleaser.yaml already exists" {
-			fmt.Printf("Warning: Could not generate release script: %v\n", err)
+		// Try to generate the release script if applicable for this language,
+		// but don't fail if it already exists
+		err = langProvider.GenerateReleaseScript(cmd.Context(), project)
+		if err != nil && !errors.Is(err, scerrors.ErrAlreadyExists) {
+			warnf("Could not generate release script: %v", err)
 		}
 		
 		// Update configuration
@@ -90,7 +92,7 @@ var addCICmd = &cobra.Command{
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 		
-		fmt.Printf("CI workflows for provider '%s' successfully added to the project\n", 
+		logf("CI workflows for provider '%s' successfully added to the project", 
 			providerName)
 		return nil
 	},
@@ -111,16 +113,21 @@ var addPlatformCmd = &cobra.Command{
 		}
 		
 		// Load configuration
-		configManager := config.NewManager(projectPath)
+		configManager := newConfigManager(projectPath)
 		if err := configManager.Load(); err != nil {
 			return fmt.Errorf("unable to load configuration: %w", err)
 		}
 		
-		// Get language provider
 		pluginLoader := plugin.NewPluginLoader()
 		registerPlugins(pluginLoader)
-		
-		langProvider, err := pluginLoader.GetLanguageProvider(configManager.Config.Language)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		// Get language provider
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
 		if err != nil {
 			return fmt.Errorf("language provider not available: %w", err)
 		}
@@ -131,7 +138,9 @@ var addPlatformCmd = &cobra.Command{
 		}
 		
 		// Add platform to project
-		if err := langProvider.AddPlatform(projectPath, platformName); err != nil {
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.SetTargets(cmd.Context(), newProject(projectPath, configManager.Config), configManager.EffectiveTargets(langProvider))
+		}); err != nil {
 			return fmt.Errorf("failed to add platform: %w", err)
 		}
 		
@@ -140,7 +149,7 @@ var addPlatformCmd = &cobra.Command{
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 		
-		fmt.Printf("Platform '%s' successfully added to the project\n", platformName)
+		logf("Platform '%s' successfully added to the project", platformName)
 		return nil
 	},
 }
@@ -160,16 +169,21 @@ var addReleaseAssetCmd = &cobra.Command{
 		}
 		
 		// Load configuration
-		configManager := config.NewManager(projectPath)
+		configManager := newConfigManager(projectPath)
 		if err := configManager.Load(); err != nil {
 			return fmt.Errorf("unable to load configuration: %w", err)
 		}
 		
-		// Get language provider
 		pluginLoader := plugin.NewPluginLoader()
 		registerPlugins(pluginLoader)
-		
-		langProvider, err := pluginLoader.GetLanguageProvider(configManager.Config.Language)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		// Get language provider
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
 		if err != nil {
 			return fmt.Errorf("language provider not available: %w", err)
 		}
@@ -180,7 +194,9 @@ var addReleaseAssetCmd = &cobra.Command{
 		}
 		
 		// Add release asset to project
-		if err := langProvider.AddReleaseAsset(projectPath, assetType); err != nil {
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.AddReleaseAsset(cmd.Context(), newProject(projectPath, configManager.Config), assetType)
+		}); err != nil {
 			return fmt.Errorf("failed to add release asset: %w", err)
 		}
 		
@@ -189,7 +205,7 @@ var addReleaseAssetCmd = &cobra.Command{
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 		
-		fmt.Printf("Release asset type '%s' successfully added to the project\n", assetType)
+		logf("Release asset type '%s' successfully added to the project", assetType)
 		return nil
 	},
 }
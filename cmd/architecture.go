@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/caezarr-oss/scotter/pkg/config"
 	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -24,16 +24,21 @@ var addArchitectureCmd = &cobra.Command{
 		}
 		
 		// Load configuration
-		configManager := config.NewManager(projectPath)
+		configManager := newConfigManager(projectPath)
 		if err := configManager.Load(); err != nil {
 			return fmt.Errorf("unable to load configuration: %w", err)
 		}
 		
-		// Get language provider
 		pluginLoader := plugin.NewPluginLoader()
 		registerPlugins(pluginLoader)
-		
-		langProvider, err := pluginLoader.GetLanguageProvider(configManager.Config.Language)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		// Get language provider
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
 		if err != nil {
 			return fmt.Errorf("language provider not available: %w", err)
 		}
@@ -43,15 +48,19 @@ var addArchitectureCmd = &cobra.Command{
 			return fmt.Errorf("unable to add architecture: %w", err)
 		}
 		
-		// Architecture changes only affect the configuration file
-		// No need to update language provider
+		// Add architecture to project
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.SetTargets(cmd.Context(), newProject(projectPath, configManager.Config), configManager.EffectiveTargets(langProvider))
+		}); err != nil {
+			return fmt.Errorf("failed to add architecture: %w", err)
+		}
 		
 		// Save configuration
 		if err := configManager.Save(); err != nil {
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 		
-		fmt.Printf("Architecture '%s' successfully added to the project\n", archName)
+		logf("Architecture '%s' successfully added to the project", archName)
 		return nil
 	},
 }
@@ -71,25 +80,38 @@ var removeArchitectureCmd = &cobra.Command{
 		}
 		
 		// Load configuration
-		configManager := config.NewManager(projectPath)
+		configManager := newConfigManager(projectPath)
 		if err := configManager.Load(); err != nil {
 			return fmt.Errorf("unable to load configuration: %w", err)
 		}
 		
+		// Get language provider
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+		
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
+		if err != nil {
+			return fmt.Errorf("language provider not available: %w", err)
+		}
+
 		// Remove architecture from configuration
 		if err := configManager.RemoveArchitecture(archName); err != nil {
 			return fmt.Errorf("unable to remove architecture: %w", err)
 		}
 		
-		// Architecture changes only affect the configuration file
-		// No need to update language provider
+		// Remove architecture from project
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.SetTargets(cmd.Context(), newProject(projectPath, configManager.Config), configManager.EffectiveTargets(langProvider))
+		}); err != nil {
+			return fmt.Errorf("failed to remove architecture: %w", err)
+		}
 		
 		// Save configuration
 		if err := configManager.Save(); err != nil {
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 		
-		fmt.Printf("Architecture '%s' successfully removed from the project\n", archName)
+		logf("Architecture '%s' successfully removed from the project", archName)
 		return nil
 	},
 }
@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and maintain .scotter.yaml",
+	Long:  `Inspect and maintain the project configuration stored in .scotter.yaml.`,
+}
+
+var (
+	schemaWrite bool
+	configJSON  bool
+	showOrigin  bool
+)
+
+// newConfigManager creates a configuration manager reading and writing
+// .scotter.yaml through the transaction of the command
+func newConfigManager(projectPath string) *config.Manager {
+	configManager := config.NewManager(projectPath)
+	configManager.FS = txn.FS
+	return configManager
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the effective settings",
+	Long: `List every effective setting with its value. Settings are merged from, in
+increasing precedence:
+
+  default   the values built into Scotter
+  user      ~/.config/scotter/config.yaml (or $XDG_CONFIG_HOME/scotter/config.yaml)
+  profile   the profile given with --profile, as scotter init --profile applies it
+  project   .scotter.yaml, when run from a project
+  env       SCOTTER_* environment variables, such as SCOTTER_CI_PROVIDER
+
+The flags of commands such as scotter init override these settings when given,
+and are not listed. Use --show-origin to print where each value comes from, or --json to print
+the settings and their origin as JSON.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		userSettings, err := config.UserSettings()
+		if err != nil {
+			return err
+		}
+		layers := [][]config.Setting{config.DefaultSettings(), userSettings}
+		if profileName != "" {
+			profileSettings, err := config.ProfileSettings(profileName)
+			if err != nil {
+				return err
+			}
+			layers = append(layers, profileSettings)
+		}
+
+		// Load configuration, when run from a project
+		configManager := newConfigManager(projectPath)
+		if _, err := txn.Stat(configManager.ConfigPath); err == nil {
+			if err := configManager.Load(); err != nil {
+				var invalid *config.ValidationError
+				if !errors.As(err, &invalid) {
+					return fmt.Errorf("unable to load configuration: %w", err)
+				}
+			}
+			projectSettings, err := configManager.Settings()
+			if err != nil {
+				return fmt.Errorf("unable to read configuration: %w", err)
+			}
+			layers = append(layers, projectSettings)
+		}
+		settings := config.Resolve(append(layers, config.EnvSettings())...)
+
+		if structuredOutput() {
+			setResult(settings)
+			return nil
+		}
+		if configJSON {
+			data, err := json.MarshalIndent(settings, "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to encode settings: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, setting := range settings {
+			value := fmt.Sprint(setting.Value)
+			if list, ok := setting.Value.([]string); ok {
+				value = strings.Join(list, ",")
+			}
+			if showOrigin {
+				fmt.Fprintf(w, "%s\t%s=%s\n", setting.Where(), setting.Key, value)
+			} else {
+				fmt.Fprintf(w, "%s=%s\n", setting.Key, value)
+			}
+		}
+		return w.Flush()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a configuration value",
+	Long: `Print the value of a configuration key. Keys are dotted paths, such as
+project_type, extra_config.docker.registry or targets.0.
+
+Lists are printed one item per line and mappings as YAML. Use --json to print
+the value as JSON instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		// Load configuration
+		configManager := newConfigManager(projectPath)
+		if err := configManager.Load(); err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+
+		value, err := configManager.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if structuredOutput() {
+			setResult(map[string]interface{}{"key": args[0], "value": value})
+			return nil
+		}
+		return printValue(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value...]",
+	Short: "Set a configuration value",
+	Long: `Set a configuration key to a value. Keys are dotted paths, such as
+project_type or extra_config.docker.registry.
+
+List keys, such as targets or release_assets, are set to every value given.
+Values under extra_config are read as YAML, so 5000 is a number and true a
+boolean. .scotter.yaml is edited in place, keeping its comments and key order,
+and the change is refused if it makes the configuration invalid.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editConfig(func(configManager *config.Manager, pluginLoader plugin.PluginLoader) error {
+			return configManager.Set(args[0], args[1:], pluginLoader)
+		})
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset [key]",
+	Short: "Remove a configuration value",
+	Long: `Remove a configuration key, such as extra_config.docker.registry, from
+.scotter.yaml. Mappings left empty are removed as well.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editConfig(func(configManager *config.Manager, pluginLoader plugin.PluginLoader) error {
+			return configManager.Unset(args[0], pluginLoader)
+		})
+	},
+}
+
+// editConfig applies an edit to the configuration of the current project
+func editConfig(edit func(configManager *config.Manager, pluginLoader plugin.PluginLoader) error) error {
+	// Get current directory as project path
+	projectPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("unable to resolve project path: %w", err)
+	}
+
+	// Load configuration
+	configManager := newConfigManager(projectPath)
+	if err := configManager.Load(); err != nil {
+		var invalid *config.ValidationError
+		if !errors.As(err, &invalid) {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+		// Editing is how the problems get fixed
+	}
+
+	pluginLoader := plugin.NewPluginLoader()
+	registerPlugins(pluginLoader)
+
+	if err := edit(configManager, pluginLoader); err != nil {
+		var invalid *config.ValidationError
+		if errors.As(err, &invalid) {
+			return fmt.Errorf("the change would make the configuration invalid:\n%w", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// printValue prints a configuration value, as JSON with --json
+func printValue(value interface{}) error {
+	if configJSON {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("unable to encode value: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("unable to encode value: %w", err)
+		}
+		fmt.Print(string(data))
+	case []interface{}:
+		for _, item := range v {
+			if err := printValue(item); err != nil {
+				return err
+			}
+		}
+	case nil:
+		fmt.Println()
+	default:
+		fmt.Println(v)
+	}
+	return nil
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade .scotter.yaml to the current schema version",
+	Long: `Upgrade .scotter.yaml to the schema version of this Scotter, applying every
+migration between the version the file was written with and the current one.
+
+Older files are already migrated in memory whenever they are loaded; this
+command writes the result back, editing the file in place so that its
+comments and key order are kept. Use the global --dry-run flag to preview the
+changes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		configManager := newConfigManager(projectPath)
+		if _, err := txn.Stat(configManager.ConfigPath); err != nil {
+			return fmt.Errorf("unable to read configuration: %w", err)
+		}
+
+		applied, err := configManager.Migrate()
+		if err != nil {
+			return fmt.Errorf("unable to migrate configuration: %w", err)
+		}
+		if len(applied) == 0 {
+			logf("%s is already at schema version %d", config.DefaultConfigFile, config.CurrentSchemaVersion)
+			return nil
+		}
+
+		for _, migration := range applied {
+			logf("%d: %s", migration.Version, migration.Description)
+		}
+
+		if dryRun {
+			logf("%s would be migrated to schema version %d", config.DefaultConfigFile, config.CurrentSchemaVersion)
+		} else {
+			logf("%s migrated to schema version %d", config.DefaultConfigFile, config.CurrentSchemaVersion)
+		}
+		return nil
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of .scotter.yaml",
+	Long: `Print the JSON Schema of .scotter.yaml, listing the project types, targets,
+release assets and CI providers of the registered plugins.
+
+With --write, the schema is written to .scotter/schema.json, which the
+yaml-language-server header of .scotter.yaml points editors to.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+
+		if !schemaWrite {
+			data, err := json.MarshalIndent(config.GenerateSchema(pluginLoader), "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to encode schema: %w", err)
+			}
+			if structuredOutput() {
+				// Decode the schema so that YAML keeps its JSON keys
+				var schema interface{}
+				if err := json.Unmarshal(data, &schema); err != nil {
+					return fmt.Errorf("unable to encode schema: %w", err)
+				}
+				setResult(schema)
+				return nil
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+		if err := writeSchema(projectPath, pluginLoader); err != nil {
+			return err
+		}
+
+		logf("Schema written to %s", config.SchemaFile)
+		return nil
+	},
+}
+
+// writeSchema writes the JSON Schema of the configuration to the project
+func writeSchema(projectPath string, pluginLoader plugin.PluginLoader) error {
+	data, err := json.MarshalIndent(config.GenerateSchema(pluginLoader), "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode schema: %w", err)
+	}
+
+	schemaPath := filepath.Join(projectPath, filepath.FromSlash(config.SchemaFile))
+	if err := txn.MkdirAll(filepath.Dir(schemaPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", config.SchemaFile, err)
+	}
+	if err := txn.WriteFile(schemaPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", config.SchemaFile, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+
+	configGetCmd.Flags().BoolVar(&configJSON, "json", false, "Print the value as JSON")
+	configListCmd.Flags().BoolVar(&showOrigin, "show-origin", false, "Print where each value comes from")
+	configListCmd.Flags().BoolVar(&configJSON, "json", false, "Print the settings as JSON")
+	configListCmd.Flags().StringVar(&profileName, "profile", "", "Include the settings of a profile")
+	configSchemaCmd.Flags().BoolVar(&schemaWrite, "write", false, "Write the schema to "+config.SchemaFile)
+}
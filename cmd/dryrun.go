@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/internal/diff"
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+)
+
+// dryRun runs the command against an in-memory copy of the filesystem
+var dryRun bool
+
+// treeNode is a directory or file of the tree of changes
+type treeNode struct {
+	name     string
+	kind     string
+	children map[string]*treeNode
+}
+
+// printDryRun prints the files a transaction would create, modify or delete,
+// as a tree followed by their diffs, and the steps it would run
+func printDryRun(tx *txn.Tx) {
+	changes := tx.Changes()
+	steps := tx.Steps()
+
+	fmt.Println()
+	if len(changes) == 0 && len(steps) == 0 {
+		fmt.Println("Dry run, nothing would be changed")
+		return
+	}
+	fmt.Println("Dry run, no file was changed")
+
+	if len(changes) > 0 {
+		root := &treeNode{children: make(map[string]*treeNode)}
+		for _, change := range changes {
+			node := root
+			parts := strings.Split(filepath.ToSlash(displayPath(change.Path)), "/")
+			for _, part := range parts {
+				child, ok := node.children[part]
+				if !ok {
+					child = &treeNode{name: part, children: make(map[string]*treeNode)}
+					node.children[part] = child
+				}
+				node = child
+			}
+			node.kind = change.Kind
+		}
+
+		fmt.Println()
+		printTree(root, "")
+
+		for _, change := range changes {
+			fmt.Println()
+			fmt.Print(changeDiff(change))
+		}
+	}
+
+	if len(steps) > 0 {
+		fmt.Println()
+		fmt.Println("Would run:")
+		for _, step := range steps {
+			fmt.Printf("  %s\n", step.Description)
+		}
+	}
+}
+
+// changeDiff returns the unified diff of a change, with paths relative to
+// the working directory, as printed with --dry-run and in the results of
+// --output json or yaml
+func changeDiff(change vfs.Change) string {
+	path := filepath.ToSlash(displayPath(change.Path))
+	oldName, newName := "a/"+path, "b/"+path
+	switch change.Kind {
+	case vfs.Created:
+		oldName = "/dev/null"
+	case vfs.Deleted:
+		newName = "/dev/null"
+	}
+	return diff.Unified(oldName, newName, string(change.Before), string(change.After))
+}
+
+// printTree prints the children of a node, directories first
+func printTree(node *treeNode, indent string) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := node.children[names[i]], node.children[names[j]]
+		if (a.kind == "") != (b.kind == "") {
+			return a.kind == ""
+		}
+		return names[i] < names[j]
+	})
+
+	for i, name := range names {
+		child := node.children[name]
+		branch, next := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		if child.kind == "" {
+			fmt.Printf("%s%s%s/\n", indent, branch, name)
+			printTree(child, indent+next)
+			continue
+		}
+		fmt.Printf("%s%s%s (%s)\n", indent, branch, name, child.kind)
+	}
+}
+
+// displayPath returns a path relative to the working directory when it lies
+// below it, and the absolute path otherwise
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
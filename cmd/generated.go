@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/caezarr-oss/scotter/internal/lockfile"
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/provider"
+)
+
+// renderGeneratedFiles renders every file Scotter generates for a project:
+// the project scaffolding and the files derived from its configuration
+func renderGeneratedFiles(ctx context.Context, projectPath string, cfg *config.Config, pluginLoader plugin.PluginLoader) ([]plugin.GeneratedFile, error) {
+	langProvider, err := provider.Language(pluginLoader, cfg.Language)
+	if err != nil {
+		return nil, fmt.Errorf("language provider not available: %w", err)
+	}
+
+	files, err := langProvider.RenderProject(ctx, newProject(projectPath, cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render project: %w", err)
+	}
+
+	derived, err := renderDerivedFiles(ctx, projectPath, cfg, pluginLoader)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, derived...), nil
+}
+
+// renderDerivedFiles renders every file derived from the project
+// configuration: the CI workflows and, when the project has one, the release
+// script
+func renderDerivedFiles(ctx context.Context, projectPath string, cfg *config.Config, pluginLoader plugin.PluginLoader) ([]plugin.GeneratedFile, error) {
+	langProvider, err := provider.Language(pluginLoader, cfg.Language)
+	if err != nil {
+		return nil, fmt.Errorf("language provider not available: %w", err)
+	}
+	project := newProject(projectPath, cfg)
+
+	var files []plugin.GeneratedFile
+	if cfg.CIProvider != "" {
+		ciProvider, err := provider.CI(pluginLoader, cfg.CIProvider)
+		if err != nil {
+			return nil, fmt.Errorf("CI provider not available: %w", err)
+		}
+		workflows, err := ciProvider.RenderWorkflows(ctx, project)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render workflows: %w", err)
+		}
+		files = append(files, workflows...)
+	}
+
+	releaseFiles, err := langProvider.RenderReleaseScript(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render release script: %w", err)
+	}
+
+	// Like init, projects without a CI provider only get a release script
+	// they already have
+	for _, file := range releaseFiles {
+		if cfg.CIProvider == "" {
+			if _, err := txn.Stat(filepath.Join(projectPath, filepath.FromSlash(file.Path))); err != nil {
+				continue
+			}
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// trackEdits runs an in-place edit of generated files; the files that were
+// unmodified before the edit are recorded with their new content, so the edit
+// is not mistaken for a user change
+func trackEdits(projectPath string, edit func() error) error {
+	lock, err := lockfile.Load(txn.FS, projectPath)
+	if err != nil {
+		return fmt.Errorf("unable to load lockfile: %w", err)
+	}
+
+	var pristine []string
+	for path := range lock.Files {
+		content, err := txn.ReadFile(filepath.Join(projectPath, filepath.FromSlash(path)))
+		if err == nil && !lock.Modified(path, content) {
+			pristine = append(pristine, path)
+		}
+	}
+
+	if err := edit(); err != nil {
+		return err
+	}
+	if len(pristine) == 0 {
+		return nil
+	}
+
+	for _, path := range pristine {
+		if content, err := txn.ReadFile(filepath.Join(projectPath, filepath.FromSlash(path))); err == nil {
+			lock.Update(path, content)
+		}
+	}
+	if err := lock.Save(); err != nil {
+		return fmt.Errorf("unable to save lockfile: %w", err)
+	}
+	return nil
+}
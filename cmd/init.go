@@ -1,24 +1,43 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
+	"github.com/caezarr-oss/scotter/internal/txn"
+	golangplugin "github.com/caezarr-oss/scotter/internal/cmd/golang"
 	"github.com/caezarr-oss/scotter/pkg/config"
 	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/provider"
 	"github.com/spf13/cobra"
 )
 
 var (
-	projectType string
-	language    string
+	projectType   string
+	language      string
+	noInteractive bool
+	answersFile   string
+	modulePath    string
+	goVersion     string
+	profileName   string
 )
 
 var initCmd = &cobra.Command{
 	Use:   "init [project-name]",
 	Short: "Initialize a new project",
-	Long:  `Initialize a new project with the specified structure and settings`,
+	Long: `Initialize a new project with the specified structure and settings.
+
+When run from a terminal without any flag, an interactive wizard asks for the
+module path, project type, platforms, architectures, release assets and CI
+provider. Use --answers to provide those settings from a YAML file, or
+--no-interactive to apply the defaults.
+
+--profile applies a named preset defined in the user configuration or in the
+profiles directory of an organization template directory. Flags and the
+answers file override the values of the profile.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectName := args[0]
@@ -27,71 +46,146 @@ var initCmd = &cobra.Command{
 			return fmt.Errorf("unable to resolve project path: %w", err)
 		}
 
-		// Create project directory
-		if err := os.MkdirAll(projectPath, 0755); err != nil {
-			return fmt.Errorf("unable to create project directory: %w", err)
+		// Resolve the defaults from the built-in settings, the user configuration,
+		// the profile and the environment, in increasing precedence
+		userSettings, err := config.UserSettings()
+		if err != nil {
+			return err
+		}
+		var profileSettings []config.Setting
+		if profileName != "" {
+			if profileSettings, err = config.ProfileSettings(profileName); err != nil {
+				return err
+			}
+		}
+		settings := config.Resolve(config.DefaultSettings(), userSettings, profileSettings, config.EnvSettings())
+		if !cmd.Flags().Changed("language") {
+			language = config.LookupString(settings, "language")
 		}
 
-		// Initialize configuration
-		configManager := config.NewManager(projectPath)
-		configManager.Config.ProjectName = projectName
-		configManager.Config.ProjectType = projectType
-		configManager.Config.Language = language
-		
 		// Get language provider
 		pluginLoader := plugin.NewPluginLoader()
 		registerPlugins(pluginLoader)
 		
-		langProvider, err := pluginLoader.GetLanguageProvider(language)
+		langProvider, err := provider.Language(pluginLoader, language)
 		if err != nil {
 			return fmt.Errorf("language provider not available: %w", err)
 		}
-		
-		// Add default platforms
-		if err := configManager.AddPlatform("linux", langProvider); err != nil {
-			// Just log the error and continue, as this is initial setup
-			fmt.Printf("Warning: Failed to add platform 'linux': %s\n", err)
+
+		// Collect the init settings from the answers file, the wizard or the defaults
+		answers := defaultAnswers(projectPath, projectName, settings)
+		if answersFile != "" {
+			if err := loadAnswers(answersFile, &answers); err != nil {
+				return err
+			}
 		}
-		if err := configManager.AddPlatform("darwin", langProvider); err != nil {
-			fmt.Printf("Warning: Failed to add platform 'darwin': %s\n", err)
+
+		// Explicit flags take precedence over the answers file
+		if cmd.Flags().Changed("module") {
+			answers.ModulePath = modulePath
 		}
-		if err := configManager.AddPlatform("windows", langProvider); err != nil {
-			fmt.Printf("Warning: Failed to add platform 'windows': %s\n", err)
+		if cmd.Flags().Changed("type") {
+			answers.ProjectType = projectType
 		}
-		
-		// Add default architectures
-		if err := configManager.AddArchitecture("amd64", langProvider); err != nil {
-			fmt.Printf("Warning: Failed to add architecture 'amd64': %s\n", err)
+
+		if answersFile == "" && !noInteractive && cmd.Flags().NFlag() == 0 && isTerminal(os.Stdin) {
+			var ciProviders []string
+			for _, p := range pluginLoader.GetCIProviders() {
+				if contains(p.SupportedLanguages(), language) {
+					ciProviders = append(ciProviders, p.Name())
+				}
+			}
+			sort.Strings(ciProviders)
+
+			w := &wizard{ctx: cmd.Context(), in: bufio.NewReader(os.Stdin), out: os.Stderr}
+			confirmed, err := w.run(&answers, langProvider, ciProviders)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				return fmt.Errorf("project initialization aborted")
+			}
 		}
-		if err := configManager.AddArchitecture("arm64", langProvider); err != nil {
-			fmt.Printf("Warning: Failed to add architecture 'arm64': %s\n", err)
+
+		var ciProvider provider.CIProvider
+		if answers.CIProvider != "" {
+			if ciProvider, err = provider.CI(pluginLoader, answers.CIProvider); err != nil {
+				return fmt.Errorf("CI provider not available: %w", err)
+			}
+		}
+
+		// Create project directory
+		if err := txn.MkdirAll(projectPath, 0755); err != nil {
+			return fmt.Errorf("unable to create project directory: %w", err)
+		}
+
+		// Initialize configuration
+		configManager := newConfigManager(projectPath)
+		configManager.Config.ProjectName = filepath.Base(projectPath)
+		configManager.Config.ModulePath = answers.ModulePath
+		configManager.Config.ProjectType = answers.ProjectType
+		configManager.Config.Language = language
+		configManager.Config.CIProvider = answers.CIProvider
+		for key, value := range answers.ExtraConfig {
+			configManager.SetExtraConfig(key, value)
+		}
+		if goVersion != "" {
+			configManager.SetExtraConfig("go_version", goVersion)
+		}
+		if answers.Author != "" {
+			configManager.SetExtraConfig("author", answers.Author)
+		}
+		if answers.License != "" {
+			configManager.SetExtraConfig("license", answers.License)
 		}
 		
-		// Add default release assets
-		if err := configManager.AddReleaseAsset("checksum", langProvider); err != nil {
-			fmt.Printf("Warning: Failed to add release asset 'checksum': %s\n", err)
+		// Add targets and release assets; invalid entries are only logged, as
+		// this is initial setup
+		for _, target := range answers.buildTargets(langProvider) {
+			if err := configManager.AddTarget(target, langProvider); err != nil {
+				warnf("Failed to add target '%s': %s", target, err)
+			}
 		}
-		if err := configManager.AddReleaseAsset("sbom", langProvider); err != nil {
-			fmt.Printf("Warning: Failed to add release asset 'sbom': %s\n", err)
+		for _, pattern := range answers.IgnoreTargets {
+			if err := configManager.AddIgnoreTarget(pattern, langProvider); err != nil {
+				warnf("Failed to ignore target '%s': %s", pattern, err)
+			}
 		}
-		if err := configManager.AddReleaseAsset("archive", langProvider); err != nil {
-			fmt.Printf("Warning: Failed to add release asset 'archive': %s\n", err)
+		for _, asset := range answers.ReleaseAssets {
+			if err := configManager.AddReleaseAsset(asset, langProvider); err != nil {
+				warnf("Failed to add release asset '%s': %s", asset, err)
+			}
 		}
 		
+		// Point editors to the schema of the configuration
+		if err := writeSchema(projectPath, pluginLoader); err != nil {
+			return err
+		}
+		configManager.Header = config.SchemaHeader + "\n"
+
 		// Save configuration
 		if err := configManager.Save(); err != nil {
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 
-		// Language provider already obtained above
-
-		// Initialize project
-		if err := langProvider.Initialize(projectName, projectType, configManager.Config.ExtraConfig); err != nil {
+		// Initialize project, exposing the module path and CI provider to the templates
+		project := newProject(projectPath, configManager.Config)
+		if err := langProvider.Initialize(cmd.Context(), project); err != nil {
 			return fmt.Errorf("failed to initialize project: %w", err)
 		}
 
-		fmt.Printf("Project '%s' successfully initialized with type '%s' using language '%s'\n", 
-			projectName, projectType, language)
+		// Generate CI workflows and release configuration when a CI provider was chosen
+		if ciProvider != nil {
+			if err := ciProvider.GenerateWorkflows(cmd.Context(), project); err != nil {
+				return fmt.Errorf("failed to generate workflows: %w", err)
+			}
+			if err := langProvider.GenerateReleaseScript(cmd.Context(), project); err != nil {
+				project.Out.Warnf("Could not generate release script: %v", err)
+			}
+		}
+
+		logf("Project '%s' successfully initialized with type '%s' using language '%s'", 
+			projectName, answers.ProjectType, language)
 		return nil
 	},
 }
@@ -102,4 +196,9 @@ func init() {
 	// Define flags
 	initCmd.Flags().StringVar(&projectType, "type", "default", "Project type (cli, api, library, default)")
 	initCmd.Flags().StringVar(&language, "language", "go", "Programming language")
+	initCmd.Flags().StringVar(&modulePath, "module", "", "Go module path (defaults to the git remote or the user module prefix)")
+	initCmd.Flags().StringVar(&goVersion, "go-version", "", "Go version of the go directive (defaults to "+golangplugin.GoVersion+")")
+	initCmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Never prompt, apply the defaults")
+	initCmd.Flags().StringVar(&answersFile, "answers", "", "YAML file answering the init questions")
+	initCmd.Flags().StringVar(&profileName, "profile", "", "Profile of the user or organization configuration to start from")
 }
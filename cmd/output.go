@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// outputFormat is the format of what commands print to the standard output
+var outputFormat string
+
+// result is what a command prints with --output json or yaml
+type result struct {
+	Command       string         `json:"command" yaml:"command"`
+	Success       bool           `json:"success" yaml:"success"`
+	DryRun        bool           `json:"dry_run" yaml:"dry_run"`
+	Files         []fileChange   `json:"files" yaml:"files"`
+	ConfigChanges []configChange `json:"config_changes" yaml:"config_changes"`
+	Steps         []string       `json:"steps" yaml:"steps"`
+	Warnings      []string       `json:"warnings" yaml:"warnings"`
+	Data          interface{}    `json:"data,omitempty" yaml:"data,omitempty"`
+	Error         *errorResult   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// fileChange is a file the command created, modified or deleted
+type fileChange struct {
+	Path   string `json:"path" yaml:"path"`
+	Change string `json:"change" yaml:"change"`
+
+	// Diff is the unified diff of the change, only given with --dry-run
+	Diff string `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// configChange is a configuration key the command set, changed or removed
+type configChange struct {
+	File string      `json:"file" yaml:"file"`
+	Key  string      `json:"key" yaml:"key"`
+	Old  interface{} `json:"old" yaml:"old"`
+	New  interface{} `json:"new" yaml:"new"`
+}
+
+// errorResult is the failure of the command
+type errorResult struct {
+	Message  string `json:"message" yaml:"message"`
+	ExitCode int    `json:"exit_code" yaml:"exit_code"`
+}
+
+// current collects the warnings and data of the running command
+var current result
+
+// structuredOutput reports whether the command prints a JSON or YAML result
+func structuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputYAML
+}
+
+// setResult sets the data of the result printed with --output json or yaml
+func setResult(data interface{}) {
+	current.Data = data
+}
+
+// logf prints a message meant for the user to the standard error, leaving
+// the standard output to the results of the command
+func logf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// warnf prints a warning and records it in the result of the command
+func warnf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	current.Warnings = append(current.Warnings, message)
+	logf("Warning: %s", message)
+}
+
+// logOutput is the output of providers, going through logf and warnf
+type logOutput struct{}
+
+func (logOutput) Infof(format string, args ...interface{}) {
+	logf(format, args...)
+}
+
+func (logOutput) Warnf(format string, args ...interface{}) {
+	warnf(format, args...)
+}
+
+// checkOutputFormat rejects an unknown --output format before the command runs
+func checkOutputFormat(cmd *cobra.Command, args []string) error {
+	switch outputFormat {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format '%s', expected one of %s, %s, %s",
+		outputFormat, outputText, outputJSON, outputYAML)
+}
+
+// printResult prints the result of a command with --output json or yaml:
+// the files it changed, the configuration keys it changed, its warnings and
+// data, and its error. The changes are read from the transaction before it is
+// committed or rolled back, and are nil when they were thrown away.
+func printResult(executed *cobra.Command, changes []vfs.Change, steps []txn.Step, err error) {
+	if !structuredOutput() || executed == nil || !executed.Runnable() {
+		return
+	}
+	if help, _ := executed.Flags().GetBool("help"); help {
+		return
+	}
+
+	res := current
+	res.Command = strings.TrimPrefix(executed.CommandPath(), rootCmd.Name()+" ")
+	res.Success = err == nil
+	res.DryRun = dryRun
+	res.Files = []fileChange{}
+	res.ConfigChanges = []configChange{}
+	res.Steps = []string{}
+	if res.Warnings == nil {
+		res.Warnings = []string{}
+	}
+	if err != nil {
+		res.Error = &errorResult{Message: err.Error(), ExitCode: scerrors.ExitCode(err)}
+	}
+	for _, change := range changes {
+		file := fileChange{Path: change.Path, Change: change.Kind}
+		if dryRun {
+			file.Diff = changeDiff(change)
+		}
+		res.Files = append(res.Files, file)
+		res.ConfigChanges = append(res.ConfigChanges, configChanges(change)...)
+	}
+	for _, step := range steps {
+		res.Steps = append(res.Steps, step.Description)
+	}
+
+	var data []byte
+	var encodeErr error
+	if outputFormat == outputJSON {
+		data, encodeErr = json.MarshalIndent(res, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, encodeErr = yaml.Marshal(res)
+	}
+	if encodeErr != nil {
+		logf("Warning: unable to encode the result: %s", encodeErr)
+		return
+	}
+	os.Stdout.Write(data)
+}
+
+// configChanges returns the keys a change of a configuration file sets,
+// changes or removes, and nothing for other files
+func configChanges(change vfs.Change) []configChange {
+	isConfig := filepath.Base(change.Path) == config.DefaultConfigFile
+	if userPath, err := config.UserConfigPath(); err == nil && change.Path == userPath {
+		isConfig = true
+	}
+	if !isConfig {
+		return nil
+	}
+
+	before, err := config.ParseSettings(change.Before, "", "")
+	if err != nil {
+		return nil
+	}
+	after, err := config.ParseSettings(change.After, "", "")
+	if err != nil {
+		return nil
+	}
+
+	keys := make(map[string]bool)
+	for _, setting := range append(before, after...) {
+		keys[setting.Key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var changes []configChange
+	for _, key := range sorted {
+		was, _ := config.Lookup(before, key)
+		is, _ := config.Lookup(after, key)
+		if reflect.DeepEqual(was.Value, is.Value) {
+			continue
+		}
+		changes = append(changes, configChange{File: change.Path, Key: key, Old: was.Value, New: is.Value})
+	}
+	return changes
+}
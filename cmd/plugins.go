@@ -3,14 +3,28 @@ package cmd
 import (
 	"github.com/caezarr-oss/scotter/internal/ci/github"
 	golangplugin "github.com/caezarr-oss/scotter/internal/cmd/golang"
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/config"
 	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/provider"
 )
 
-// registerPlugins registers all available plugins with the plugin loader
+// registerPlugins registers all available plugins with the plugin loader,
+// writing through the filesystem of the running command
 func registerPlugins(loader *plugin.DefaultPluginLoader) {
 	// Register language providers
-	loader.RegisterLanguageProvider(golangplugin.NewGoLanguageProvider())
+	goProvider := golangplugin.NewGoLanguageProvider()
+	goProvider.SetFilesystem(txn.FS)
+	loader.RegisterLanguageProvider(goProvider)
 	
 	// Register CI providers
-	loader.RegisterCIProvider(github.NewGitHubProvider())
+	githubProvider := github.NewGitHubProvider()
+	githubProvider.SetFilesystem(txn.FS)
+	loader.RegisterCIProvider(githubProvider)
+}
+
+// newProject returns the project providers act on, reporting their messages
+// on the standard error and their warnings in the result of the command
+func newProject(projectPath string, cfg *config.Config) *provider.Project {
+	return &provider.Project{Root: projectPath, Config: cfg, Out: logOutput{}}
 }
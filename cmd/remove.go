@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -29,25 +30,38 @@ var removePlatformCmd = &cobra.Command{
 		}
 		
 		// Load configuration
-		configManager := config.NewManager(projectPath)
+		configManager := newConfigManager(projectPath)
 		if err := configManager.Load(); err != nil {
 			return fmt.Errorf("unable to load configuration: %w", err)
 		}
 		
+		// Get language provider
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+		
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
+		if err != nil {
+			return fmt.Errorf("language provider not available: %w", err)
+		}
+
 		// Remove platform from configuration
 		if err := configManager.RemovePlatform(platformName); err != nil {
 			return fmt.Errorf("unable to remove platform: %w", err)
 		}
 		
-		// Platform changes only affect the configuration file
-		// No need to update language provider
+		// Remove platform from project
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.SetTargets(cmd.Context(), newProject(projectPath, configManager.Config), configManager.EffectiveTargets(langProvider))
+		}); err != nil {
+			return fmt.Errorf("failed to remove platform: %w", err)
+		}
 		
 		// Save configuration
 		if err := configManager.Save(); err != nil {
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 		
-		fmt.Printf("Platform '%s' successfully removed from the project\n", platformName)
+		logf("Platform '%s' successfully removed from the project", platformName)
 		return nil
 	},
 }
@@ -67,25 +81,38 @@ var removeReleaseAssetCmd = &cobra.Command{
 		}
 		
 		// Load configuration
-		configManager := config.NewManager(projectPath)
+		configManager := newConfigManager(projectPath)
 		if err := configManager.Load(); err != nil {
 			return fmt.Errorf("unable to load configuration: %w", err)
 		}
 		
+		// Get language provider
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+		
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
+		if err != nil {
+			return fmt.Errorf("language provider not available: %w", err)
+		}
+
 		// Remove asset type from configuration
 		if err := configManager.RemoveReleaseAsset(assetType); err != nil {
 			return fmt.Errorf("unable to remove release asset type: %w", err)
 		}
 		
-		// Asset type changes only affect the configuration file
-		// No need to update language provider
+		// Remove release asset from project
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.RemoveReleaseAsset(cmd.Context(), newProject(projectPath, configManager.Config), assetType)
+		}); err != nil {
+			return fmt.Errorf("failed to remove release asset: %w", err)
+		}
 		
 		// Save configuration
 		if err := configManager.Save(); err != nil {
 			return fmt.Errorf("unable to save configuration: %w", err)
 		}
 		
-		fmt.Printf("Release asset type '%s' successfully removed from the project\n", assetType)
+		logf("Release asset type '%s' successfully removed from the project", assetType)
 		return nil
 	},
 }
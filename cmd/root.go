@@ -2,9 +2,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
 	"github.com/spf13/cobra"
 )
 
+// errInterrupted is returned when the user interrupts a command
+var errInterrupted = fmt.Errorf("%w, the project was left unchanged", scerrors.ErrInterrupted)
+
 var rootCmd = &cobra.Command{
 	Use:   "scotter",
 	Short: "Scotter is a scaffolding tool for Go projects",
@@ -12,13 +25,70 @@ var rootCmd = &cobra.Command{
 project structures with integrated CI/CD workflows.
 
 It supports multiple project types and CI providers.`,
+	PersistentPreRunE: checkOutputFormat,
 }
 
-// Execute runs the root command
+// Execute runs the root command. The files it writes are staged and only
+// committed once it succeeds, so that a failing command leaves the project
+// as it was; only a conflict error keeps them, as the conflict markers are
+// left for the user to resolve. With --dry-run, the staged changes are
+// printed instead. With
+// --output json or yaml, the result of the command is printed last, even
+// when it fails.
+//
+// An interrupt cancels the context of the command, which stops the providers
+// and rolls the changes back; a second interrupt ends Scotter right away.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	tx := txn.Begin()
+	executed, err := rootCmd.ExecuteContextC(ctx)
+	changes, steps := tx.Changes(), tx.Steps()
+
+	// A conflict still leaves changes to write, the conflict markers
+	var conflict *scerrors.ConflictError
+	switch {
+	case err != nil && !errors.As(err, &conflict):
+		tx.Rollback()
+		changes, steps = nil, nil
+		if ctx.Err() != nil {
+			err = errInterrupted
+		}
+	case dryRun:
+		if !structuredOutput() {
+			printDryRun(tx)
+		}
+		if rollbackErr := tx.Rollback(); err == nil {
+			err = rollbackErr
+		}
+	case ctx.Err() != nil:
+		tx.Rollback()
+		changes, steps = nil, nil
+		err = errInterrupted
+	default:
+		if commitErr := tx.Commit(); commitErr != nil {
+			changes, steps = nil, nil
+			if ctx.Err() != nil {
+				err = errInterrupted
+			} else {
+				err = fmt.Errorf("unable to write changes, the project was left unchanged: %w", commitErr)
+			}
+		}
+	}
+
+	printResult(executed, changes, steps, err)
+	return err
 }
 
 func init() {
-	// Add commands will be registered here
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show the files the command would change without writing them")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Format of the results printed to the standard output: text, json or yaml")
+
+	// Warnings of the configuration end up in the result of the command
+	config.Warnf = warnf
 }
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/caezarr-oss/scotter/internal/lockfile"
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+// fileStatus is the status of a generated file, as reported with --output
+type fileStatus struct {
+	Path     string `json:"path" yaml:"path"`
+	Status   string `json:"status" yaml:"status"`
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+	Version  string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// Status of a generated file
+const (
+	statusPristine = "pristine"
+	statusModified = "user-modified"
+	statusMissing  = "missing"
+	statusOrphaned = "orphaned"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of generated files",
+	Long: `Show the status of every file recorded in .scotter.lock:
+
+  pristine       unchanged since Scotter generated it
+  user-modified  edited since Scotter generated it
+  missing        deleted from the project
+  orphaned       no longer generated for the current configuration`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		// Load configuration
+		configManager := newConfigManager(projectPath)
+		if err := configManager.Load(); err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+
+		lock, err := lockfile.Load(txn.FS, projectPath)
+		if err != nil {
+			return fmt.Errorf("unable to load lockfile: %w", err)
+		}
+		if len(lock.Files) == 0 {
+			if structuredOutput() {
+				setResult([]fileStatus{})
+				return nil
+			}
+			fmt.Printf("No generated files recorded in %s\n", lockfile.File)
+			return nil
+		}
+
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		files, err := renderGeneratedFiles(cmd.Context(), projectPath, configManager.Config, pluginLoader)
+		if err != nil {
+			return err
+		}
+		generated := make(map[string]bool, len(files))
+		for _, file := range files {
+			generated[file.Path] = true
+		}
+
+		paths := make([]string, 0, len(lock.Files))
+		for path := range lock.Files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		statuses := make([]fileStatus, 0, len(paths))
+		for _, path := range paths {
+			status := statusPristine
+			content, err := txn.ReadFile(filepath.Join(projectPath, filepath.FromSlash(path)))
+			switch {
+			case os.IsNotExist(err):
+				status = statusMissing
+			case err != nil:
+				return fmt.Errorf("unable to read %s: %w", path, err)
+			case !generated[path]:
+				status = statusOrphaned
+			case lock.Modified(path, content):
+				status = statusModified
+			}
+
+			entry := lock.Files[path]
+			statuses = append(statuses, fileStatus{Path: path, Status: status, Template: entry.Template, Version: entry.Version})
+			if structuredOutput() {
+				continue
+			}
+
+			source := entry.Template
+			if source == "" {
+				source = "(native)"
+			}
+			if entry.Version != "" {
+				source += "@" + entry.Version
+			}
+			fmt.Printf("%-15s %-35s %s\n", status, path, source)
+		}
+		setResult(statuses)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
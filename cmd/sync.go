@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/internal/diff"
+	"github.com/caezarr-oss/scotter/internal/lockfile"
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncWrite bool
+	syncForce bool
+)
+
+// syncResult is a generated file out of date, as reported with --output
+type syncResult struct {
+	Path         string `json:"path" yaml:"path"`
+	UserModified bool   `json:"user_modified" yaml:"user_modified"`
+	Untracked    bool   `json:"untracked" yaml:"untracked"`
+	Diff         string `json:"diff" yaml:"diff"`
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Regenerate derived files from .scotter.yaml",
+	Long: `Regenerate the CI workflows and the release script from .scotter.yaml,
+which is the source of truth for the files Scotter derives from it.
+
+By default, sync only shows a unified diff of every file it would change. Use
+--write to apply the changes. Files edited since Scotter last generated them,
+and existing files Scotter did not generate, are only overwritten with --force.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		// Load configuration
+		configManager := newConfigManager(projectPath)
+		if err := configManager.Load(); err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		files, err := renderDerivedFiles(cmd.Context(), projectPath, configManager.Config, pluginLoader)
+		if err != nil {
+			return err
+		}
+
+		lock, err := lockfile.Load(txn.FS, projectPath)
+		if err != nil {
+			return fmt.Errorf("unable to load lockfile: %w", err)
+		}
+
+		// Show the changes, noting the files edited since they were generated
+		// and the files Scotter did not generate
+		var changed, modified, untracked []string
+		results := []syncResult{}
+		defer func() { setResult(results) }()
+		for _, file := range files {
+			current, err := txn.ReadFile(filepath.Join(projectPath, filepath.FromSlash(file.Path)))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("unable to read %s: %w", file.Path, err)
+			}
+			if err == nil && bytes.Equal(current, file.Content) {
+				continue
+			}
+
+			oldName := "a/" + file.Path
+			_, tracked := lock.Files[file.Path]
+			userModified, notTracked := false, false
+			switch {
+			case os.IsNotExist(err):
+				oldName = "/dev/null"
+			case !tracked:
+				untracked = append(untracked, file.Path)
+				notTracked = true
+			case lock.Modified(file.Path, current):
+				modified = append(modified, file.Path)
+				userModified = true
+			}
+			fileDiff := diff.Unified(oldName, "b/"+file.Path, string(current), string(file.Content))
+			results = append(results, syncResult{Path: file.Path, UserModified: userModified, Untracked: notTracked, Diff: fileDiff})
+			if !structuredOutput() {
+				fmt.Print(fileDiff)
+			}
+			changed = append(changed, file.Path)
+		}
+
+		if len(changed) == 0 {
+			logf("All generated files are up to date")
+			return nil
+		}
+		if !syncWrite {
+			logf("\n%d file(s) would be changed, run 'scotter sync --write' to apply", len(changed))
+			if len(modified) > 0 {
+				logf("Modified since they were generated, only overwritten with --force: %s", strings.Join(modified, ", "))
+			}
+			if len(untracked) > 0 {
+				logf("Not generated by Scotter, only overwritten with --force: %s", strings.Join(untracked, ", "))
+			}
+			return nil
+		}
+		if !syncForce {
+			var refused []string
+			if len(modified) > 0 {
+				refused = append(refused, "files modified since they were generated: "+strings.Join(modified, ", "))
+			}
+			if len(untracked) > 0 {
+				refused = append(refused, "files not generated by Scotter: "+strings.Join(untracked, ", "))
+			}
+			if len(refused) > 0 {
+				return fmt.Errorf("refusing to overwrite %s (use --force to overwrite them)", strings.Join(refused, "; "))
+			}
+		}
+
+		// Write every rendered file, so unchanged ones are recorded as well
+		if err := lockfile.WriteFiles(txn.FS, projectPath, files); err != nil {
+			return err
+		}
+
+		logf("\n%d file(s) updated", len(changed))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVar(&syncWrite, "write", false, "Apply the changes instead of only showing them")
+	syncCmd.Flags().BoolVar(&syncForce, "force", false, "Overwrite files modified since they were generated or not generated by Scotter")
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+var targetIgnore bool
+
+var addTargetCmd = &cobra.Command{
+	Use:   "target [os/arch]",
+	Short: "Add a build target",
+	Long: `Add an os/arch build target, such as linux/riscv64, validated against the
+targets the language supports. A * wildcard selects every matching target,
+for example linux/* or */arm64.
+
+With --ignore, add an ignore rule instead: targets matching the pattern are
+excluded from the build, for example linux/mips*.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := args[0]
+
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		// Load configuration
+		configManager := newConfigManager(projectPath)
+		if err := configManager.Load(); err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		// Get language provider
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
+		if err != nil {
+			return fmt.Errorf("language provider not available: %w", err)
+		}
+
+		// Add target or ignore rule to configuration
+		if targetIgnore {
+			err = configManager.AddIgnoreTarget(target, langProvider)
+		} else {
+			err = configManager.AddTarget(target, langProvider)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to add target: %w", err)
+		}
+
+		// Update the project build matrix
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.SetTargets(cmd.Context(), newProject(projectPath, configManager.Config), configManager.EffectiveTargets(langProvider))
+		}); err != nil {
+			return fmt.Errorf("failed to add target: %w", err)
+		}
+
+		// Save configuration
+		if err := configManager.Save(); err != nil {
+			return fmt.Errorf("unable to save configuration: %w", err)
+		}
+
+		if targetIgnore {
+			logf("Targets matching '%s' are now ignored", target)
+		} else {
+			logf("Target '%s' successfully added to the project", target)
+		}
+		return nil
+	},
+}
+
+var removeTargetCmd = &cobra.Command{
+	Use:   "target [os/arch]",
+	Short: "Remove a build target",
+	Long: `Remove an os/arch build target, or with --ignore an ignore rule, as written
+in .scotter.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := args[0]
+
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		// Load configuration
+		configManager := newConfigManager(projectPath)
+		if err := configManager.Load(); err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+
+		// Get language provider
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+
+		langProvider, err := provider.Language(pluginLoader, configManager.Config.Language)
+		if err != nil {
+			return fmt.Errorf("language provider not available: %w", err)
+		}
+
+		// Remove target or ignore rule from configuration
+		if targetIgnore {
+			err = configManager.RemoveIgnoreTarget(target)
+		} else {
+			err = configManager.RemoveTarget(target)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to remove target: %w", err)
+		}
+
+		// Update the project build matrix
+		if err := trackEdits(projectPath, func() error {
+			return langProvider.SetTargets(cmd.Context(), newProject(projectPath, configManager.Config), configManager.EffectiveTargets(langProvider))
+		}); err != nil {
+			return fmt.Errorf("failed to remove target: %w", err)
+		}
+
+		// Save configuration
+		if err := configManager.Save(); err != nil {
+			return fmt.Errorf("unable to save configuration: %w", err)
+		}
+
+		logf("Target '%s' successfully removed from the project", target)
+		return nil
+	},
+}
+
+func init() {
+	addCmd.AddCommand(addTargetCmd)
+	removeCmd.AddCommand(removeTargetCmd)
+
+	addTargetCmd.Flags().BoolVar(&targetIgnore, "ignore", false, "Add an ignore rule excluding the matching targets")
+	removeTargetCmd.Flags().BoolVar(&targetIgnore, "ignore", false, "Remove an ignore rule")
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/internal/embedded"
+	"github.com/caezarr-oss/scotter/internal/packs"
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Inspect and manage templates",
+	Long: `Inspect and manage the templates used to scaffold projects.
+
+Templates are resolved through layers, from highest priority down:
+project-local .scotter/templates, the user directory ~/.config/scotter/templates,
+the organization directories listed in SCOTTER_TEMPLATE_PATH and finally the
+templates embedded in Scotter. A file in a higher layer shadows the same path
+in the layers below it.`,
+}
+
+// packResult is an installed template pack, as reported with --output
+type packResult struct {
+	Name    string   `json:"name" yaml:"name"`
+	Source  string   `json:"source" yaml:"source"`
+	Version string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Commit  string   `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Types   []string `json:"types" yaml:"types"`
+}
+
+// layerResult is a layer providing a template, as reported with --output
+type layerResult struct {
+	Name string `json:"name" yaml:"name"`
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+var templatesWhichCmd = &cobra.Command{
+	Use:   "which [path]",
+	Short: "Show which layer provides a template",
+	Long:  `Show which template layer provides a path, such as golang/cli/main.go.tmpl`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templatePath := filepath.ToSlash(args[0])
+
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		templateManager := embedded.NewTemplateManager(projectPath)
+		sources := templateManager.Sources(templatePath)
+		if len(sources) == 0 {
+			return fmt.Errorf("template '%s' not found in any layer", templatePath)
+		}
+
+		if structuredOutput() {
+			layers := make([]layerResult, 0, len(sources))
+			for _, layer := range sources {
+				path := ""
+				if layer.Root != "" {
+					path = filepath.Join(layer.Root, filepath.FromSlash(templatePath))
+				}
+				layers = append(layers, layerResult{Name: layer.Name, Path: path})
+			}
+			setResult(map[string]interface{}{"template": templatePath, "layers": layers})
+			return nil
+		}
+
+		fmt.Printf("%s is provided by the %s layer", templatePath, sources[0].Name)
+		if sources[0].Root != "" {
+			fmt.Printf(" (%s)", filepath.Join(sources[0].Root, filepath.FromSlash(templatePath)))
+		}
+		fmt.Println()
+
+		for _, layer := range sources[1:] {
+			fmt.Printf("  shadows the %s layer", layer.Name)
+			if layer.Root != "" {
+				fmt.Printf(" (%s)", filepath.Join(layer.Root, filepath.FromSlash(templatePath)))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var packName string
+
+var templatesInstallCmd = &cobra.Command{
+	Use:   "install [path-or-git-url]",
+	Short: "Install a template pack",
+	Long: `Install a template pack from a git repository (local or remote) or a .tar.gz archive.
+
+Every directory of the pack holding a template.yaml manifest becomes a project
+type usable with 'scotter init --type <pack>/<type>'. Installing a pack with
+the name of an installed one replaces it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Packs are fetched and unpacked outside of the project files
+		if dryRun {
+			return fmt.Errorf("templates install does not support --dry-run")
+		}
+
+		store, err := packs.NewStore()
+		if err != nil {
+			return err
+		}
+
+		pack, err := store.Install(args[0], packName)
+		if err != nil {
+			return fmt.Errorf("failed to install template pack: %w", err)
+		}
+
+		types, err := store.Types(pack.Name)
+		if err != nil {
+			return err
+		}
+
+		setResult(packResult{Name: pack.Name, Source: pack.Source, Version: pack.Version, Commit: pack.Commit, Types: types})
+		logf("Template pack '%s' %s successfully installed", pack.Name, packRevision(*pack))
+		for _, t := range types {
+			logf("  scotter init <project-name> --type %s/%s", pack.Name, t)
+		}
+		return nil
+	},
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed template packs",
+	Long:  `List installed template packs with their version or commit and the project types they provide`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := packs.NewStore()
+		if err != nil {
+			return err
+		}
+
+		installed, err := store.List()
+		if err != nil {
+			return err
+		}
+		if len(installed) == 0 && !structuredOutput() {
+			fmt.Println("No template packs installed")
+			return nil
+		}
+
+		results := make([]packResult, 0, len(installed))
+		for _, pack := range installed {
+			types, err := store.Types(pack.Name)
+			if err != nil {
+				return err
+			}
+			results = append(results, packResult{Name: pack.Name, Source: pack.Source, Version: pack.Version, Commit: pack.Commit, Types: types})
+			if structuredOutput() {
+				continue
+			}
+
+			fmt.Printf("%s %s\n", pack.Name, packRevision(pack))
+			fmt.Printf("  source: %s\n", pack.Source)
+			fmt.Printf("  types:  %s\n", strings.Join(types, ", "))
+		}
+		setResult(results)
+		return nil
+	},
+}
+
+var templatesRemoveCmd = &cobra.Command{
+	Use:   "remove [pack]",
+	Short: "Remove an installed template pack",
+	Long:  `Remove an installed template pack from the user template store`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun {
+			return fmt.Errorf("templates remove does not support --dry-run")
+		}
+
+		store, err := packs.NewStore()
+		if err != nil {
+			return err
+		}
+
+		if err := store.Remove(args[0]); err != nil {
+			return fmt.Errorf("unable to remove template pack: %w", err)
+		}
+
+		logf("Template pack '%s' successfully removed", args[0])
+		return nil
+	},
+}
+
+// packRevision describes the installed version of a pack
+func packRevision(pack packs.Pack) string {
+	switch {
+	case pack.Version != "" && pack.Commit != "":
+		return fmt.Sprintf("%s (%.12s)", pack.Version, pack.Commit)
+	case pack.Version != "":
+		return pack.Version
+	case pack.Commit != "":
+		return fmt.Sprintf("(%.12s)", pack.Commit)
+	default:
+		return "(unversioned)"
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesWhichCmd)
+	templatesCmd.AddCommand(templatesInstallCmd)
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesRemoveCmd)
+
+	templatesInstallCmd.Flags().StringVar(&packName, "name", "", "Install the pack under this name instead of the one it declares")
+}
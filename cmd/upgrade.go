@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caezarr-oss/scotter/internal/diff"
+	"github.com/caezarr-oss/scotter/internal/lockfile"
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+	"github.com/spf13/cobra"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade generated files to the current templates",
+	Long: `Upgrade the files Scotter generated to the templates of this version.
+
+Files left untouched since they were generated are replaced. Files you edited
+are merged three ways: the content generated at the time (kept in
+.scotter/base), your current file and the newly rendered template. Lines both
+sides changed differently are left between conflict markers to resolve by hand.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		// Load configuration
+		configManager := newConfigManager(projectPath)
+		if err := configManager.Load(); err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+
+		lock, err := lockfile.Load(txn.FS, projectPath)
+		if err != nil {
+			return fmt.Errorf("unable to load lockfile: %w", err)
+		}
+
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+
+		// Validate configuration against the registered plugins
+		if err := configManager.Validate(pluginLoader); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+
+		files, err := renderGeneratedFiles(cmd.Context(), projectPath, configManager.Config, pluginLoader)
+		if err != nil {
+			return err
+		}
+
+		var conflicted []string
+		upgraded := make([]upgradeResult, 0, len(files))
+		defer func() { setResult(upgraded) }()
+		for _, file := range files {
+			result, content, err := upgradeFile(projectPath, lock, file)
+			if err != nil {
+				return err
+			}
+			if content != nil {
+				target := filepath.Join(projectPath, filepath.FromSlash(file.Path))
+				if err := txn.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					return fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+				}
+				if err := txn.WriteFile(target, content, 0644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", file.Path, err)
+				}
+			}
+			upgraded = append(upgraded, upgradeResult{
+				Path:            file.Path,
+				Result:          result,
+				Version:         file.Version,
+				PreviousVersion: lock.Files[file.Path].Version,
+			})
+			if !structuredOutput() {
+				fmt.Printf("%-10s %-35s %s\n", result, file.Path, versionChange(lock, file))
+			}
+
+			if result != upgradeSkipped {
+				lock.Record(file)
+			}
+			if result == upgradeConflict {
+				conflicted = append(conflicted, file.Path)
+			}
+		}
+
+		if err := lock.Save(); err != nil {
+			return fmt.Errorf("unable to save lockfile: %w", err)
+		}
+
+		// The changes are kept, conflict markers included
+		if len(conflicted) > 0 {
+			return &scerrors.ConflictError{Files: conflicted}
+		}
+		return nil
+	},
+}
+
+// Result of the upgrade of a file
+const (
+	upgradeUnchanged = "unchanged"
+	upgradeAdded     = "added"
+	upgradeUpdated   = "updated"
+	upgradeMerged    = "merged"
+	upgradeConflict  = "conflict"
+	upgradeSkipped   = "skipped"
+)
+
+// upgradeResult is the upgrade of a file, as reported with --output
+type upgradeResult struct {
+	Path            string `json:"path" yaml:"path"`
+	Result          string `json:"result" yaml:"result"`
+	Version         string `json:"version,omitempty" yaml:"version,omitempty"`
+	PreviousVersion string `json:"previous_version,omitempty" yaml:"previous_version,omitempty"`
+}
+
+// upgradeFile decides how a file is upgraded and returns the content to
+// write, nil when the file is left as is
+func upgradeFile(projectPath string, lock *lockfile.Lock, file plugin.GeneratedFile) (string, []byte, error) {
+	_, tracked := lock.Files[file.Path]
+	current, err := txn.ReadFile(filepath.Join(projectPath, filepath.FromSlash(file.Path)))
+	switch {
+	case os.IsNotExist(err) && tracked:
+		// Deleted on purpose, keep it deleted
+		return upgradeSkipped, nil, nil
+	case os.IsNotExist(err):
+		return upgradeAdded, file.Content, nil
+	case err != nil:
+		return "", nil, fmt.Errorf("unable to read %s: %w", file.Path, err)
+	}
+
+	switch {
+	case bytes.Equal(current, file.Content):
+		return upgradeUnchanged, nil, nil
+	case !tracked:
+		// Not generated by Scotter, leave it alone
+		return upgradeSkipped, nil, nil
+	case !lock.Modified(file.Path, current):
+		return upgradeUpdated, file.Content, nil
+	}
+
+	// Without the generated content, every edit conflicts with the new template
+	base, err := lock.Base(file.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("unable to read base of %s: %w", file.Path, err)
+	}
+
+	merged, conflicts := diff.Merge3(string(base), string(current), string(file.Content), "current", "upgrade")
+	if merged == string(current) {
+		// The template did not change the lines the user kept
+		return upgradeUnchanged, nil, nil
+	}
+	if conflicts > 0 {
+		return upgradeConflict, []byte(merged), nil
+	}
+	return upgradeMerged, []byte(merged), nil
+}
+
+// versionChange describes the template version change of a file
+func versionChange(lock *lockfile.Lock, file plugin.GeneratedFile) string {
+	previous := lock.Files[file.Path].Version
+	if previous == "" || previous == file.Version {
+		return file.Version
+	}
+	return previous + " -> " + file.Version
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+}
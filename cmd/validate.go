@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check .scotter.yaml for mistakes",
+	Long: `Check .scotter.yaml for unknown keys, values of the wrong type, and project
+types, CI providers, targets and release assets the registered plugins do not
+support. Every problem is reported with its file:line:column.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get current directory as project path
+		projectPath, err := filepath.Abs(".")
+		if err != nil {
+			return fmt.Errorf("unable to resolve project path: %w", err)
+		}
+
+		pluginLoader := plugin.NewPluginLoader()
+		registerPlugins(pluginLoader)
+
+		// Load and validate configuration
+		configManager := newConfigManager(projectPath)
+		err = configManager.Check(pluginLoader)
+
+		var invalid *config.ValidationError
+		if errors.As(err, &invalid) {
+			setResult(invalid.Diagnostics)
+			fmt.Fprintln(os.Stderr, invalid)
+			return fmt.Errorf("%w: %d problem(s) found in %s", scerrors.ErrConfigInvalid, len(invalid.Diagnostics), invalid.File)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+
+		logf("%s is valid", config.DefaultConfigFile)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
@@ -17,6 +17,12 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version information of Scotter",
 	Long:  `Display the version, commit, build date and other information about the Scotter binary.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if structuredOutput() {
+			// Report the build information, the version included
+			setResult(version.BuildInfo())
+			return
+		}
+
 		if showDetailedVersion {
 			// Display detailed information
 			buildInfo := version.BuildInfo()
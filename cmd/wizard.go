@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"gopkg.in/yaml.v3"
+)
+
+// initAnswers holds every choice made when initializing a project, whether
+// it comes from the wizard, an answers file or the resolved settings
+type initAnswers struct {
+	ModulePath    string   `yaml:"module_path"`
+	ProjectType   string   `yaml:"project_type"`
+	Platforms     []string `yaml:"platforms"`
+	Architectures []string `yaml:"architectures"`
+	Targets       []string `yaml:"targets,omitempty"`
+	ReleaseAssets []string `yaml:"release_assets"`
+	CIProvider    string   `yaml:"ci_provider"`
+	IgnoreTargets []string `yaml:"ignore_targets,omitempty"`
+	Author        string   `yaml:"author,omitempty"`
+	License       string   `yaml:"license,omitempty"`
+
+	// ExtraConfig holds settings passed to the templates, set by profiles
+	ExtraConfig map[string]interface{} `yaml:"extra_config,omitempty"`
+}
+
+// defaultAnswers returns the answers given by the settings resolved from the
+// built-in defaults, the user configuration, the profile and the environment
+func defaultAnswers(projectPath, projectName string, settings []config.Setting) initAnswers {
+	answers := initAnswers{
+		ModulePath:    config.LookupString(settings, "module_path"),
+		ProjectType:   config.LookupString(settings, "project_type"),
+		Targets:       config.LookupList(settings, "targets"),
+		ReleaseAssets: config.LookupList(settings, "release_assets"),
+		CIProvider:    config.LookupString(settings, "ci_provider"),
+		IgnoreTargets: config.LookupList(settings, "ignore_targets"),
+		Author:        config.LookupString(settings, "author"),
+		License:       config.LookupString(settings, "license"),
+	}
+	for _, setting := range settings {
+		key, ok := strings.CutPrefix(setting.Key, "extra_config.")
+		if !ok {
+			continue
+		}
+		if answers.ExtraConfig == nil {
+			answers.ExtraConfig = make(map[string]interface{})
+		}
+
+		// Dotted keys such as docker.registry are nested mappings
+		values, path := answers.ExtraConfig, strings.Split(key, ".")
+		for _, element := range path[:len(path)-1] {
+			nested, ok := values[element].(map[string]interface{})
+			if !ok {
+				nested = make(map[string]interface{})
+				values[element] = nested
+			}
+			values = nested
+		}
+		values[path[len(path)-1]] = setting.Value
+	}
+	if answers.ModulePath == "" {
+		answers.ModulePath = config.DefaultModulePath(projectPath, projectName, config.LookupString(settings, "module_prefix"))
+	}
+
+	// Platforms and architectures are the elements of the default targets
+	for _, target := range answers.Targets {
+		if parsed, err := config.ParseTarget(target); err == nil {
+			if !contains(answers.Platforms, parsed.OS) {
+				answers.Platforms = append(answers.Platforms, parsed.OS)
+			}
+			if !contains(answers.Architectures, parsed.Arch) {
+				answers.Architectures = append(answers.Architectures, parsed.Arch)
+			}
+		}
+	}
+	return answers
+}
+
+// resetTargets drops the targets once the platforms or architectures differ
+// from the ones they were listed with, so that buildTargets combines the new ones
+func (a *initAnswers) resetTargets(platforms, architectures []string) {
+	if strings.Join(a.Platforms, ",") != strings.Join(platforms, ",") ||
+		strings.Join(a.Architectures, ",") != strings.Join(architectures, ",") {
+		a.Targets = nil
+	}
+}
+
+// buildTargets returns the explicit os/arch targets of the answers, or else
+// every supported combination of the chosen platforms and architectures
+func (a *initAnswers) buildTargets(langProvider plugin.LanguageSupport) []string {
+	if len(a.Targets) > 0 {
+		return a.Targets
+	}
+
+	var targets []string
+	for _, platform := range a.Platforms {
+		for _, arch := range a.Architectures {
+			if target := platform + "/" + arch; langProvider.IsSupportedTarget(target) {
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets
+}
+
+// loadAnswers overrides answers with the values set in an answers file
+func loadAnswers(path string, answers *initAnswers) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read answers file: %w", err)
+	}
+
+	// Targets set by the file are kept, whatever its platforms and architectures
+	platforms, architectures, targets := answers.Platforms, answers.Architectures, answers.Targets
+	answers.Targets = nil
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(answers); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid answers file %s: %w", path, err)
+	}
+	if answers.Targets == nil {
+		answers.Targets = targets
+		answers.resetTargets(platforms, architectures)
+	}
+	return nil
+}
+
+// isTerminal reports whether a file is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// wizard asks the init questions on a terminal, until ctx is cancelled
+type wizard struct {
+	ctx context.Context
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// run asks every question, starting from the current answers as defaults,
+// then shows a summary and asks for confirmation
+func (w *wizard) run(answers *initAnswers, langProvider plugin.LanguageSupport, ciProviders []string) (bool, error) {
+	var err error
+
+	if answers.ModulePath, err = w.ask("Module path", answers.ModulePath); err != nil {
+		return false, err
+	}
+	if answers.ProjectType, err = w.choose("Project type", answers.ProjectType, langProvider.SupportedProjectTypes()); err != nil {
+		return false, err
+	}
+	platforms, architectures := answers.Platforms, answers.Architectures
+	if answers.Platforms, err = w.chooseMany("Platforms", answers.Platforms, langProvider.GetSupportedPlatforms()); err != nil {
+		return false, err
+	}
+	if answers.Architectures, err = w.chooseMany("Architectures", answers.Architectures, langProvider.GetSupportedArchitectures()); err != nil {
+		return false, err
+	}
+	answers.resetTargets(platforms, architectures)
+	if answers.ReleaseAssets, err = w.chooseMany("Release assets", answers.ReleaseAssets, langProvider.GetSupportedReleaseAssets()); err != nil {
+		return false, err
+	}
+
+	ciProvider := answers.CIProvider
+	if ciProvider == "" {
+		ciProvider = "none"
+	}
+	if ciProvider, err = w.choose("CI provider", ciProvider, append(ciProviders, "none")); err != nil {
+		return false, err
+	}
+	if ciProvider == "none" {
+		ciProvider = ""
+	}
+	answers.CIProvider = ciProvider
+
+	fmt.Fprintln(w.out)
+	printAnswers(w.out, answers)
+	fmt.Fprintln(w.out)
+
+	confirm, err := w.ask("Create project with these settings? [Y/n]", "")
+	if err != nil {
+		return false, err
+	}
+	confirm = strings.ToLower(confirm)
+	return confirm == "" || confirm == "y" || confirm == "yes", nil
+}
+
+// ask prints a question and returns the answer, or the default on an empty line
+func (w *wizard) ask(question, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	// The answer is read aside, a pending read cannot be interrupted
+	type answer struct {
+		line string
+		err  error
+	}
+	answers := make(chan answer, 1)
+	go func() {
+		line, err := w.in.ReadString('\n')
+		answers <- answer{line: line, err: err}
+	}()
+
+	var line string
+	select {
+	case <-w.ctx.Done():
+		fmt.Fprintln(w.out)
+		return "", w.ctx.Err()
+	case a := <-answers:
+		if a.err != nil && (a.err != io.EOF || a.line == "") {
+			return "", fmt.Errorf("unable to read answer: %w", a.err)
+		}
+		line = a.line
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return defaultValue, nil
+	}
+	return line, nil
+}
+
+// choose asks for a single value among the supported ones
+func (w *wizard) choose(question, defaultValue string, supported []string) (string, error) {
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s (%s)", question, strings.Join(supported, ", ")), defaultValue)
+		if err != nil {
+			return "", err
+		}
+		if contains(supported, answer) {
+			return answer, nil
+		}
+		fmt.Fprintf(w.out, "'%s' is not supported\n", answer)
+	}
+}
+
+// chooseMany asks for a comma-separated list of supported values
+func (w *wizard) chooseMany(question string, defaults, supported []string) ([]string, error) {
+	fmt.Fprintf(w.out, "Supported %s: %s\n", strings.ToLower(question), strings.Join(supported, ", "))
+	for {
+		answer, err := w.ask(question+" (comma separated)", strings.Join(defaults, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		values := splitList(answer)
+		valid := true
+		for _, v := range values {
+			if !contains(supported, v) {
+				fmt.Fprintf(w.out, "'%s' is not supported\n", v)
+				valid = false
+			}
+		}
+		if valid {
+			return values, nil
+		}
+	}
+}
+
+// printAnswers prints a summary of the init settings
+func printAnswers(out io.Writer, answers *initAnswers) {
+	ciProvider := answers.CIProvider
+	if ciProvider == "" {
+		ciProvider = "none"
+	}
+
+	fmt.Fprintln(out, "Summary:")
+	fmt.Fprintf(out, "  %-15s %s\n", "Module path:", answers.ModulePath)
+	fmt.Fprintf(out, "  %-15s %s\n", "Project type:", answers.ProjectType)
+	if len(answers.Targets) > 0 {
+		fmt.Fprintf(out, "  %-15s %s\n", "Targets:", strings.Join(answers.Targets, ", "))
+	} else {
+		fmt.Fprintf(out, "  %-15s %s\n", "Platforms:", strings.Join(answers.Platforms, ", "))
+		fmt.Fprintf(out, "  %-15s %s\n", "Architectures:", strings.Join(answers.Architectures, ", "))
+	}
+	fmt.Fprintf(out, "  %-15s %s\n", "Release assets:", strings.Join(answers.ReleaseAssets, ", "))
+	fmt.Fprintf(out, "  %-15s %s\n", "CI provider:", ciProvider)
+	if answers.Author != "" {
+		fmt.Fprintf(out, "  %-15s %s\n", "Author:", answers.Author)
+	}
+	if answers.License != "" {
+		fmt.Fprintf(out, "  %-15s %s\n", "License:", answers.License)
+	}
+}
+
+// splitList splits a comma-separated answer, dropping empty entries
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// contains checks if a slice contains a string
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
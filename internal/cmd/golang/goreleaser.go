@@ -0,0 +1,329 @@
+package golang
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+	"github.com/caezarr-oss/scotter/pkg/yamledit"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// goreleaserFile is the GoReleaser configuration maintained by the provider
+	goreleaserFile = ".goreleaser.yaml"
+
+	// releaseTemplateRoot is the directory holding the GoReleaser templates
+	releaseTemplateRoot = "goreleaser"
+)
+
+// releaseData is the data the GoReleaser templates are rendered with
+type releaseData struct {
+	ProjectName   string
+	ProjectType   string
+	ModulePath    string
+	Main          string
+	Matrix        buildMatrix
+	ReleaseAssets []string
+}
+
+// Scalar formats a value as a YAML scalar, quoting it like the in-place edits do
+func (d releaseData) Scalar(value string) string {
+	return yamledit.FormatScalar(value)
+}
+
+// HasAsset reports whether a release asset type is enabled
+func (d releaseData) HasAsset(assetType string) bool {
+	return contains(d.ReleaseAssets, assetType)
+}
+
+// releaseAssetSections maps each release asset type to the GoReleaser section producing it
+var releaseAssetSections = map[string]string{
+	"checksum": "checksum",
+	"sbom":     "sboms",
+	"archive":  "archives",
+}
+
+// matrixKeys are the build keys holding the build matrix, in the order they are written
+var matrixKeys = []string{"goos", "goarch", "goarm", "goamd64", "gomips", "go386", "ignore"}
+
+// enabledAssetSections holds the section added for each release asset type
+var enabledAssetSections = map[string]string{
+	"checksum": `name_template: 'checksums.txt'`,
+	"sboms":    `- artifacts: archive`,
+	"archives": `- format_overrides:
+  - goos: windows
+    format: zip
+  name_template: >-
+    {{ .ProjectName }}_
+    {{- title .Os }}_
+    {{- if eq .Arch "amd64" }}x86_64
+    {{- else if eq .Arch "386" }}i386
+    {{- else }}{{ .Arch }}{{ end }}
+    {{- if .Arm }}v{{ .Arm }}{{ end }}
+    {{- with .Mips }}_{{ . }}{{ end }}
+    {{- if not (eq .Amd64 "v1") }}{{ .Amd64 }}{{ end }}`,
+}
+
+// disabledAssetSections holds the section replacing a removed release asset
+// type that GoReleaser would otherwise produce by default
+var disabledAssetSections = map[string]string{
+	"checksum": `disable: true`,
+	"archives": `- format: binary`,
+}
+
+// editGoReleaserConfig applies edit to the .goreleaser.yaml of fsys, preserving
+// the comments and keys it does not touch; a missing file is left alone
+func editGoReleaserConfig(fsys vfs.FS, projectPath string, edit func(doc *yamledit.Document) error) error {
+	path := filepath.Join(projectPath, goreleaserFile)
+	data, err := fsys.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	doc, err := yamledit.Parse(data)
+	if err != nil {
+		return fmt.Errorf("unable to parse %s: %w", goreleaserFile, err)
+	}
+	if err := edit(doc); err != nil {
+		return fmt.Errorf("unable to update %s: %w", goreleaserFile, err)
+	}
+
+	return fsys.WriteFile(path, doc.Bytes(), 0644)
+}
+
+// binaryBuilds returns the indexes of the builds producing binaries, skipped
+// builds (libraries) being ignored
+func binaryBuilds(doc *yamledit.Document) []int {
+	builds := doc.Get("builds")
+	if builds == nil || builds.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var indexes []int
+	for i := range builds.Content {
+		if skip := doc.Get("builds", i, "skip"); skip != nil && skip.Value == "true" {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// buildMatrix is the GoReleaser build matrix producing exactly a set of
+// os/arch[/variant] targets: the product of its goos, goarch and variant
+// lists, minus the combinations it ignores
+type buildMatrix struct {
+	Goos     []string
+	Goarch   []string
+	Variants []variantList
+	Ignore   []ignoreRule
+}
+
+// variantList holds the values of a variant setting, such as goarm
+type variantList struct {
+	Key    string
+	Values []string
+}
+
+// ignoreRule excludes a goos/goarch combination from a build, or only one
+// of its variants when VariantKey is set
+type ignoreRule struct {
+	Goos       string
+	Goarch     string
+	VariantKey string
+	Variant    string
+}
+
+// newBuildMatrix computes the build matrix of a set of targets; targets
+// without variant are built for the GoReleaser default variant
+func newBuildMatrix(targets []string) buildMatrix {
+	var matrix buildMatrix
+	wanted := make(map[ignoreRule]bool, len(targets))
+	variants := make(map[string][]string)
+	for _, target := range targets {
+		t, err := config.ParseTarget(target)
+		if err != nil {
+			continue
+		}
+		rule := ignoreRule{Goos: t.OS, Goarch: t.Arch}
+		if setting, ok := variantSetting(t.Arch); ok {
+			variant := t.Variant
+			if variant == "" {
+				variant = setting.Default
+			}
+			rule.VariantKey, rule.Variant = setting.Key, variant
+			if !contains(variants[setting.Key], variant) {
+				variants[setting.Key] = append(variants[setting.Key], variant)
+			}
+		}
+		wanted[rule] = true
+		if !contains(matrix.Goos, t.OS) {
+			matrix.Goos = append(matrix.Goos, t.OS)
+		}
+		if !contains(matrix.Goarch, t.Arch) {
+			matrix.Goarch = append(matrix.Goarch, t.Arch)
+		}
+	}
+
+	// Only the default variant is built when a setting is left out
+	for _, setting := range goVariantSettings {
+		if values := variants[setting.Key]; len(values) > 1 || (len(values) == 1 && values[0] != setting.Default) {
+			matrix.Variants = append(matrix.Variants, variantList{Key: setting.Key, Values: values})
+		}
+	}
+
+	for _, goos := range matrix.Goos {
+		for _, goarch := range matrix.Goarch {
+			key, values := "", []string{""}
+			if setting, ok := variantSetting(goarch); ok {
+				key, values = setting.Key, matrix.variants(setting)
+			}
+
+			var unwanted []ignoreRule
+			for _, variant := range values {
+				if rule := (ignoreRule{goos, goarch, key, variant}); !wanted[rule] {
+					unwanted = append(unwanted, rule)
+				}
+			}
+			if len(unwanted) == len(values) {
+				// No variant is wanted, ignore the whole combination
+				matrix.Ignore = append(matrix.Ignore, ignoreRule{Goos: goos, Goarch: goarch})
+				continue
+			}
+			matrix.Ignore = append(matrix.Ignore, unwanted...)
+		}
+	}
+	return matrix
+}
+
+// variants returns the values of a variant setting the matrix builds
+func (m buildMatrix) variants(setting goVariantSetting) []string {
+	for _, list := range m.Variants {
+		if list.Key == setting.Key {
+			return list.Values
+		}
+	}
+	return []string{setting.Default}
+}
+
+// nodes returns the YAML value of every non-empty matrix key
+func (m buildMatrix) nodes() map[string]*yaml.Node {
+	nodes := make(map[string]*yaml.Node)
+	if len(m.Goos) > 0 {
+		nodes["goos"] = sequenceNode(m.Goos)
+	}
+	if len(m.Goarch) > 0 {
+		nodes["goarch"] = sequenceNode(m.Goarch)
+	}
+	for _, list := range m.Variants {
+		nodes[list.Key] = sequenceNode(list.Values)
+	}
+	if len(m.Ignore) > 0 {
+		ignore := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, rule := range m.Ignore {
+			pairs := []string{"goos", rule.Goos, "goarch", rule.Goarch}
+			if rule.VariantKey != "" {
+				pairs = append(pairs, rule.VariantKey, rule.Variant)
+			}
+			ignore.Content = append(ignore.Content, mappingNode(pairs...))
+		}
+		nodes["ignore"] = ignore
+	}
+	return nodes
+}
+
+// setBuildMatrix replaces the build matrix of every binary build; the matrix
+// keys are rewritten after the other keys, as the templates write them
+func setBuildMatrix(doc *yamledit.Document, matrix buildMatrix) error {
+	nodes := matrix.nodes()
+	for _, i := range binaryBuilds(doc) {
+		path := []interface{}{"builds", i}
+		for _, key := range matrixKeys {
+			if _, err := doc.Delete(path, key); err != nil {
+				return err
+			}
+		}
+		for _, key := range matrixKeys {
+			if node, ok := nodes[key]; ok {
+				if err := doc.Set(path, key, node); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addAssetSection adds the section producing a release asset type, replacing
+// the disabled form left by a previous removal
+func addAssetSection(doc *yamledit.Document, assetType string) error {
+	section := releaseAssetSections[assetType]
+	if section == "archives" && len(binaryBuilds(doc)) == 0 {
+		// Libraries do not build binaries to archive
+		return nil
+	}
+	if existing := doc.Get(section); existing != nil && !isDisabledSection(existing, section) {
+		return nil
+	}
+
+	content := enabledAssetSections[section]
+	if section == "sboms" && len(binaryBuilds(doc)) == 0 {
+		// Libraries do not build binaries, their SBOM covers the source archive
+		content = `- artifacts: source`
+	}
+
+	return doc.SetYAML(nil, section, content)
+}
+
+// removeAssetSection removes the section producing a release asset type
+func removeAssetSection(doc *yamledit.Document, assetType string) error {
+	section := releaseAssetSections[assetType]
+	disabled, ok := disabledAssetSections[section]
+	if !ok || (section == "archives" && len(binaryBuilds(doc)) == 0) {
+		_, err := doc.Delete(nil, section)
+		return err
+	}
+
+	return doc.SetYAML(nil, section, disabled)
+}
+
+// isDisabledSection reports whether a section holds its disabled form
+func isDisabledSection(node *yaml.Node, section string) bool {
+	disabled, ok := disabledAssetSections[section]
+	if !ok {
+		return false
+	}
+
+	var actual, expected interface{}
+	if err := node.Decode(&actual); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(disabled), &expected); err != nil {
+		return false
+	}
+	return fmt.Sprint(actual) == fmt.Sprint(expected)
+}
+
+// mappingNode builds a block mapping of strings from key/value pairs
+func mappingNode(pairs ...string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, v := range pairs {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+	}
+	return node
+}
+
+// sequenceNode builds a block sequence of strings
+func sequenceNode(values []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, v := range values {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+	}
+	return node
+}
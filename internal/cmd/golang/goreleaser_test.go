@@ -0,0 +1,184 @@
+package golang
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/caezarr-oss/scotter/internal/embedded"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/yamledit"
+)
+
+func TestNewBuildMatrix(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []string
+		want    buildMatrix
+	}{
+		{
+			name:    "mixed arm variants",
+			targets: []string{"linux/arm/6", "linux/arm/7"},
+			want: buildMatrix{
+				Goos:     []string{"linux"},
+				Goarch:   []string{"arm"},
+				Variants: []variantList{{Key: "goarm", Values: []string{"6", "7"}}},
+			},
+		},
+		{
+			name:    "lone non-default variant",
+			targets: []string{"linux/arm/7"},
+			want: buildMatrix{
+				Goos:     []string{"linux"},
+				Goarch:   []string{"arm"},
+				Variants: []variantList{{Key: "goarm", Values: []string{"7"}}},
+			},
+		},
+		{
+			name:    "default variant only",
+			targets: []string{"linux/arm", "darwin/arm/6"},
+			want: buildMatrix{
+				Goos:   []string{"linux", "darwin"},
+				Goarch: []string{"arm"},
+			},
+		},
+		{
+			name:    "variant missing on one os",
+			targets: []string{"linux/arm/6", "linux/arm/7", "freebsd/arm/7"},
+			want: buildMatrix{
+				Goos:     []string{"linux", "freebsd"},
+				Goarch:   []string{"arm"},
+				Variants: []variantList{{Key: "goarm", Values: []string{"6", "7"}}},
+				Ignore:   []ignoreRule{{Goos: "freebsd", Goarch: "arm", VariantKey: "goarm", Variant: "6"}},
+			},
+		},
+		{
+			name:    "sparse os and arch",
+			targets: []string{"linux/amd64", "darwin/arm64"},
+			want: buildMatrix{
+				Goos:   []string{"linux", "darwin"},
+				Goarch: []string{"amd64", "arm64"},
+				Ignore: []ignoreRule{
+					{Goos: "linux", Goarch: "arm64"},
+					{Goos: "darwin", Goarch: "amd64"},
+				},
+			},
+		},
+		{
+			name:    "full product",
+			targets: []string{"linux/amd64", "linux/arm64", "windows/amd64", "windows/arm64"},
+			want: buildMatrix{
+				Goos:   []string{"linux", "windows"},
+				Goarch: []string{"amd64", "arm64"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newBuildMatrix(tt.targets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newBuildMatrix(%q) = %+v, want %+v", tt.targets, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMatrixNodes(t *testing.T) {
+	// The default variant is built without writing its key
+	nodes := newBuildMatrix([]string{"linux/arm/6", "linux/amd64"}).nodes()
+	for _, key := range []string{"goarm", "goamd64", "ignore"} {
+		if _, ok := nodes[key]; ok {
+			t.Errorf("nodes() has %s, want only goos and goarch", key)
+		}
+	}
+
+	nodes = newBuildMatrix([]string{"linux/arm/6", "linux/arm/7", "freebsd/arm/7"}).nodes()
+	goarm, ok := nodes["goarm"]
+	if !ok || len(goarm.Content) != 2 || goarm.Content[0].Value != "6" || goarm.Content[1].Value != "7" {
+		t.Errorf("nodes() goarm = %+v, want [6, 7]", goarm)
+	}
+	ignore, ok := nodes["ignore"]
+	if !ok || len(ignore.Content) != 1 {
+		t.Fatalf("nodes() ignore = %+v, want one rule", ignore)
+	}
+	var pairs []string
+	for _, node := range ignore.Content[0].Content {
+		pairs = append(pairs, node.Value)
+	}
+	if want := []string{"goos", "freebsd", "goarch", "arm", "goarm", "6"}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("nodes() ignore rule = %q, want %q", pairs, want)
+	}
+}
+
+func TestAssetEditsMatchTemplates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	all := []string{"checksum", "sbom", "archive"}
+
+	for _, projectType := range []string{"cli", "library"} {
+		for _, assetType := range all {
+			var without []string
+			for _, asset := range all {
+				if asset != assetType {
+					without = append(without, asset)
+				}
+			}
+			enabled := renderRelease(t, projectType, all)
+			disabled := renderRelease(t, projectType, without)
+
+			t.Run(projectType+"/remove "+assetType, func(t *testing.T) {
+				got := editRelease(t, enabled, func(doc *yamledit.Document) error {
+					return removeAssetSection(doc, assetType)
+				})
+				if got != disabled {
+					t.Errorf("removeAssetSection() =\n%s\nwant the template without %s\n%s", got, assetType, disabled)
+				}
+			})
+			t.Run(projectType+"/add "+assetType, func(t *testing.T) {
+				got := editRelease(t, disabled, func(doc *yamledit.Document) error {
+					return addAssetSection(doc, assetType)
+				})
+				if got != enabled {
+					t.Errorf("addAssetSection() =\n%s\nwant the template with %s\n%s", got, assetType, enabled)
+				}
+			})
+		}
+	}
+}
+
+// renderRelease renders the embedded .goreleaser.yaml template
+func renderRelease(t *testing.T, projectType string, assets []string) string {
+	t.Helper()
+	templates := embedded.NewTemplateManager(t.TempDir())
+	manifest, err := plugin.LoadManifest(templates.Filesystem(), releaseTemplateRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := releaseData{
+		ProjectName:   "app",
+		ProjectType:   projectType,
+		ModulePath:    "example.com/app",
+		Matrix:        newBuildMatrix([]string{"linux/amd64", "darwin/arm64"}),
+		ReleaseAssets: assets,
+	}
+	if projectType != "library" {
+		data.Main = "./main.go"
+	}
+	files, err := plugin.RenderManifest(templates, manifest, data)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("RenderManifest() = %d files, %v", len(files), err)
+	}
+	return string(files[0].Content)
+}
+
+// editRelease applies an in-place edit to a .goreleaser.yaml
+func editRelease(t *testing.T, content string, edit func(doc *yamledit.Document) error) string {
+	t.Helper()
+	doc, err := yamledit.Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := edit(doc); err != nil {
+		t.Fatal(err)
+	}
+	return string(doc.Bytes())
+}
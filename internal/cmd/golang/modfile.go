@@ -0,0 +1,86 @@
+package golang
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// knownGoodDependencies pins the modules required by the template sets to
+// versions known to build with GoVersion
+var knownGoodDependencies = map[string]string{
+	"github.com/spf13/cobra":   "v1.7.0",
+	"github.com/gin-gonic/gin": "v1.9.1",
+}
+
+var (
+	// goVersionPattern matches the versions accepted by the go directive
+	goVersionPattern = regexp.MustCompile(`^1\.[0-9]+(\.[0-9]+)?$`)
+
+	// modulePathPattern is a conservative check of module path characters
+	modulePathPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~/-]*$`)
+)
+
+// moduleRequirement is a single require directive
+type moduleRequirement struct {
+	Path    string
+	Version string
+}
+
+// goModFile is the content of a go.mod file, written without the go toolchain
+type goModFile struct {
+	Module  string
+	Go      string
+	Require []moduleRequirement
+}
+
+// newGoModFile validates the module path and Go version and resolves the
+// version of each dependency, given either as a bare module path pinned in
+// knownGoodDependencies or as path@version
+func newGoModFile(modulePath, goVersion string, dependencies []string) (*goModFile, error) {
+	if !modulePathPattern.MatchString(modulePath) || strings.HasSuffix(modulePath, "/") || strings.Contains(modulePath, "//") {
+		return nil, fmt.Errorf("invalid module path '%s'", modulePath)
+	}
+	if !goVersionPattern.MatchString(goVersion) {
+		return nil, fmt.Errorf("invalid Go version '%s', expected a version such as 1.21 or 1.22.3", goVersion)
+	}
+
+	file := &goModFile{Module: modulePath, Go: goVersion}
+	for _, dependency := range dependencies {
+		path, version, pinned := strings.Cut(dependency, "@")
+		if !pinned {
+			var ok bool
+			if version, ok = knownGoodDependencies[path]; !ok {
+				return nil, fmt.Errorf("no known-good version for dependency '%s', pin it as %s@<version>", path, path)
+			}
+		}
+		if !strings.HasPrefix(version, "v") {
+			return nil, fmt.Errorf("invalid version '%s' for dependency '%s'", version, path)
+		}
+		file.Require = append(file.Require, moduleRequirement{Path: path, Version: version})
+	}
+	sort.Slice(file.Require, func(i, j int) bool { return file.Require[i].Path < file.Require[j].Path })
+
+	return file, nil
+}
+
+// Format renders the file in the canonical go.mod layout
+func (f *goModFile) Format() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "module %s\n\ngo %s\n", f.Module, f.Go)
+
+	switch len(f.Require) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&b, "\nrequire %s %s\n", f.Require[0].Path, f.Require[0].Version)
+	default:
+		b.WriteString("\nrequire (\n")
+		for _, r := range f.Require {
+			fmt.Fprintf(&b, "\t%s %s\n", r.Path, r.Version)
+		}
+		b.WriteString(")\n")
+	}
+
+	return []byte(b.String())
+}
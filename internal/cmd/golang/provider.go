@@ -2,32 +2,51 @@
 package golang
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	
 	"github.com/caezarr-oss/scotter/internal/embedded"
+	"github.com/caezarr-oss/scotter/internal/lockfile"
+	"github.com/caezarr-oss/scotter/internal/packs"
+	"github.com/caezarr-oss/scotter/internal/txn"
+	"github.com/caezarr-oss/scotter/pkg/config"
 	"github.com/caezarr-oss/scotter/pkg/plugin"
-	"gopkg.in/yaml.v3"
+	"github.com/caezarr-oss/scotter/pkg/provider"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+	"github.com/caezarr-oss/scotter/pkg/yamledit"
 )
 
 const (
-	// GoVersion is the Go version to use in project templates
+	// GoVersion is the default Go version of project templates, overridden
+	// by the go_version setting of extra_config
 	// Set to 1.21 to avoid potential compatibility issues as per identified issues
 	GoVersion = "1.21"
+
+	// templateRoot is the directory holding the Go template sets
+	templateRoot = "golang"
 )
 
 // GoLanguageProvider implements the LanguageProvider interface for Go
 type GoLanguageProvider struct {
+	// templateManager lists the project types of the working directory; the
+	// files of a project are rendered from the templates of its root
 	templateManager plugin.TemplateManager
+	fsys            vfs.FS
 }
 
 // NewGoLanguageProvider creates a new Go language provider
 func NewGoLanguageProvider() *GoLanguageProvider {
 	return &GoLanguageProvider{
-		templateManager: embedded.NewTemplateManager(),
+		templateManager: embedded.NewTemplateManager("."),
+		fsys:            vfs.OS,
 	}
 }
 
@@ -36,59 +55,145 @@ func (p *GoLanguageProvider) Name() string {
 	return "go"
 }
 
-// SupportedProjectTypes returns project types supported by this language
+// SetFilesystem sets the filesystem the project files are read from and written to
+func (p *GoLanguageProvider) SetFilesystem(fsys vfs.FS) {
+	p.fsys = fsys
+}
+
+// SupportedProjectTypes returns project types supported by this language,
+// including the Go template sets of installed packs as <pack>/<type>
 func (p *GoLanguageProvider) SupportedProjectTypes() []string {
-	return []string{"cli", "api", "library", "default"}
+	return p.projectTypes(p.templateManager.Filesystem())
 }
 
-// Initialize initializes a new project
-func (p *GoLanguageProvider) Initialize(projectName, projectType string, config map[string]interface{}) error {
-	projectDir := projectName
-	
-	// Validate project type
-	if !contains(p.SupportedProjectTypes(), projectType) {
-		return fmt.Errorf("unsupported project type '%s' for Go language", projectType)
-	}
-	
-	// Create module
-	moduleCmd := exec.Command("go", "mod", "init", fmt.Sprintf("github.com/%s", projectName))
-	moduleCmd.Dir = projectDir
-	if err := moduleCmd.Run(); err != nil {
-		return fmt.Errorf("failed to initialize Go module: %w", err)
+// projectTypes returns the project types of a template filesystem
+func (p *GoLanguageProvider) projectTypes(fsys fs.FS) []string {
+	types, err := packs.ProjectTypes(fsys, templateRoot)
+	if err != nil {
+		return nil
 	}
 
-	// Create directory structure based on project type
-	switch projectType {
-	case "cli":
-		if err := os.MkdirAll(filepath.Join(projectDir, "cmd"), 0755); err != nil {
-			return err
-		}
-		if err := os.MkdirAll(filepath.Join(projectDir, "internal"), 0755); err != nil {
-			return err
-		}
-	case "api":
-		if err := os.MkdirAll(filepath.Join(projectDir, "api"), 0755); err != nil {
-			return err
+	manifests, err := fs.Glob(fsys, path.Join(packs.Dir, "*", "*", plugin.ManifestFile))
+	if err != nil {
+		return types
+	}
+	for _, manifestPath := range manifests {
+		manifest, err := plugin.LoadManifest(fsys, path.Dir(manifestPath))
+		if err != nil || (manifest.Language != "" && manifest.Language != p.Name()) {
+			continue
 		}
-		if err := os.MkdirAll(filepath.Join(projectDir, "internal"), 0755); err != nil {
-			return err
+		types = append(types, strings.TrimPrefix(manifest.Dir, packs.Dir+"/"))
+	}
+	return types
+}
+
+// manifestDir returns the template set directory of a project type
+func manifestDir(projectType string) string {
+	if strings.Contains(projectType, "/") {
+		return path.Join(packs.Dir, projectType)
+	}
+	return path.Join(templateRoot, projectType)
+}
+
+// Initialize writes the files of a new project in its root directory
+func (p *GoLanguageProvider) Initialize(ctx context.Context, project *provider.Project) error {
+	projectDir := project.Root
+
+	templates := embedded.NewTemplateManager(project.Root)
+	manifest, data, err := p.loadTemplateSet(project, templates)
+	if err != nil {
+		return err
+	}
+
+	// Create directory structure described by the manifest
+	for _, dir := range manifest.Directories {
+		dirPath, err := plugin.RenderExpression(dir, data)
+		if err != nil {
+			return fmt.Errorf("invalid directory '%s': %w", dir, err)
 		}
-		if err := os.MkdirAll(filepath.Join(projectDir, "pkg"), 0755); err != nil {
+		if err := p.fsys.MkdirAll(filepath.Join(projectDir, filepath.FromSlash(dirPath)), 0755); err != nil {
 			return err
 		}
-	case "library":
-		if err := os.MkdirAll(filepath.Join(projectDir, "pkg"), 0755); err != nil {
-			return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	files, err := p.renderTemplateSet(templates, manifest, data)
+	if err != nil {
+		return err
+	}
+	if err := lockfile.WriteFiles(p.fsys, projectDir, files); err != nil {
+		return err
+	}
+
+	// go.mod is written natively unless the template set ships its own template
+	for _, file := range files {
+		if file.Path == "go.mod" && file.Template == "" && len(manifest.Dependencies) > 0 {
+			project.Out.Infof("Run 'go mod tidy' in %s to download dependencies and create go.sum", projectDir)
 		}
 	}
 
-	// Template paths will be used when template system is fully implemented
-	// For now we're using direct file generation
+	// The steps run commands in the project, once its files are written
+	if len(manifest.PostRender) == 0 {
+		return nil
+	}
+	description := fmt.Sprintf("post-render steps of %s in %s", project.Config.ProjectType, projectDir)
+	return txn.AfterCommit(description, func() error {
+		return runPostRenderSteps(ctx, project, manifest.PostRender, data)
+	})
+}
+
+// RenderProject renders the files of a project type without writing them
+func (p *GoLanguageProvider) RenderProject(ctx context.Context, project *provider.Project) ([]plugin.GeneratedFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	templates := embedded.NewTemplateManager(project.Root)
+	manifest, data, err := p.loadTemplateSet(project, templates)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.renderTemplateSet(templates, manifest, data)
+}
+
+// loadTemplateSet loads the manifest of a project type from the templates of
+// the project and prepares the data its templates are rendered with
+func (p *GoLanguageProvider) loadTemplateSet(project *provider.Project, templates plugin.TemplateManager) (*plugin.Manifest, map[string]interface{}, error) {
+	projectName, projectType := project.Config.ProjectName, project.Config.ProjectType
+	if projectName == "" {
+		projectName = filepath.Base(project.Root)
+	}
+	config := provider.Settings(project.Config)
+
+	// Validate project type
+	if !contains(p.projectTypes(templates.Filesystem()), projectType) {
+		return nil, nil, fmt.Errorf("unsupported project type '%s' for Go language", projectType)
+	}
+
+	manifest, err := plugin.LoadManifest(templates.Filesystem(), manifestDir(projectType))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load template manifest: %w", err)
+	}
 
 	// Prepare template data
 	data := map[string]interface{}{
 		"ProjectName": projectName,
+		"ProjectType": projectType,
+		"PackageName": packageNameFor(projectName),
+		"ModulePath":  fmt.Sprintf("github.com/%s", projectName),
 		"GoVersion":   GoVersion,
+		"CIProvider":  "",
+	}
+	if ciProvider, ok := config["ci_provider"].(string); ok {
+		data["CIProvider"] = ciProvider
+	}
+	if modulePath, ok := config["module_path"].(string); ok && modulePath != "" {
+		data["ModulePath"] = modulePath
+	}
+	if goVersion, ok := config["go_version"].(string); ok && goVersion != "" {
+		data["GoVersion"] = goVersion
 	}
 
 	// Add any additional config
@@ -96,329 +201,290 @@ func (p *GoLanguageProvider) Initialize(projectName, projectType string, config
 		data[k] = v
 	}
 
-	// Create main.go or lib.go
-	var targetPath string
-	if projectType == "library" {
-		targetPath = filepath.Join(projectDir, fmt.Sprintf("%s.go", projectName))
-	} else if projectType == "cli" {
-		targetPath = filepath.Join(projectDir, "cmd", "main.go")
-	} else {
-		targetPath = filepath.Join(projectDir, "main.go")
+	// Declared variables take their value from the config or their default
+	variables, err := manifest.ResolveVariables(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range variables {
+		data[k] = v
 	}
 
-	// Write file with basic content as placeholder since templates aren't yet implemented
-	basicContent := "package main\n\nfunc main() {\n\t// Generated by Scotter\n}\n"
-	if projectType == "library" {
-		basicContent = fmt.Sprintf("package %s\n\n// HelloWorld is an example function\nfunc HelloWorld() string {\n\treturn \"Hello from %s!\"\n}\n", projectName, projectName)
+	return manifest, data, nil
+}
+
+// renderTemplateSet renders the files of a template set, adding a natively
+// written go.mod pinning its dependencies when the set has no go.mod template
+func (p *GoLanguageProvider) renderTemplateSet(templates plugin.TemplateManager, manifest *plugin.Manifest, data map[string]interface{}) ([]plugin.GeneratedFile, error) {
+	goMod, err := newGoModFile(data["ModulePath"].(string), data["GoVersion"].(string), manifest.Dependencies)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := os.WriteFile(targetPath, []byte(basicContent), 0644); err != nil {
-		return fmt.Errorf("failed to create source file: %w", err)
+	files, err := plugin.RenderManifest(templates, manifest, data)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create README.md
-	readmePath := filepath.Join(projectDir, "README.md")
-	readmeContent := fmt.Sprintf("# %s\n\n%s project scaffolded with Scotter.\n", 
-		strings.Title(projectName), 
-		strings.Title(projectType))
-	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
-		return fmt.Errorf("failed to create README.md: %w", err)
+	for _, file := range files {
+		if file.Path == "go.mod" {
+			return files, nil
+		}
 	}
+	return append(files, plugin.GeneratedFile{Path: "go.mod", Version: manifest.Version, Content: goMod.Format()}), nil
+}
 
-	// Add .gitignore
-	gitignorePath := filepath.Join(projectDir, ".gitignore")
-	gitignoreContent := `# Binaries for programs and plugins
-*.exe
-*.exe~
-*.dll
-*.so
-*.dylib
+// runPostRenderSteps runs the manifest post-render commands inside the
+// project directory, killing the running command when ctx is cancelled
+func runPostRenderSteps(ctx context.Context, project *provider.Project, steps []plugin.PostRenderStep, data interface{}) error {
+	for _, step := range steps {
+		ok, err := plugin.EvaluateCondition(step.When, data)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
 
-# Test binary, built with "go test -c"
-*.test
+		args := strings.Fields(step.Run)
+		if len(args) == 0 {
+			continue
+		}
 
-# Output of the go coverage tool, specifically when used with LiteIDE
-*.out
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = project.Root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, exec.ErrNotFound) {
+				err = &scerrors.ToolMissingError{Tool: args[0], Purpose: "run post-render steps", Err: err}
+			}
+			if step.Optional {
+				project.Out.Warnf("post-render step '%s' failed: %v", step.Run, err)
+				continue
+			}
+			return fmt.Errorf("post-render step '%s' failed: %w\n%s", step.Run, err, output)
+		}
+	}
+	return nil
+}
 
-# Dependency directories (remove the comment below to include it)
-# vendor/
+// packageNameFor derives a valid Go package name from a project name
+func packageNameFor(projectName string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(projectName)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9' && b.Len() > 0) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "lib"
+	}
+	return b.String()
+}
+
+// GenerateReleaseScript renders .goreleaser.yaml from the project configuration,
+// using the library or executable template depending on the project type
+func (p *GoLanguageProvider) GenerateReleaseScript(ctx context.Context, project *provider.Project) error {
+	// Check if GoReleaser is already configured
+	goreleaserPath := filepath.Join(project.Root, goreleaserFile)
+	if _, err := p.fsys.Stat(goreleaserPath); err == nil {
+		return &scerrors.AlreadyExistsError{Kind: "file", Name: goreleaserFile, Path: goreleaserPath}
+	}
 
-# Go workspace file
-go.work
+	files, err := p.RenderReleaseScript(ctx, project)
+	if err != nil {
+		return err
+	}
 
-# Binary output
-/bin/
-/dist/
-`
-	if err := os.WriteFile(gitignorePath, []byte(gitignoreContent), 0644); err != nil {
-		return fmt.Errorf("failed to create .gitignore: %w", err)
+	// Write the GoReleaser configuration
+	if err := lockfile.WriteFiles(p.fsys, project.Root, files); err != nil {
+		return fmt.Errorf("failed to create GoReleaser configuration: %w", err)
 	}
 
 	return nil
 }
 
-// GenerateReleaseScript generates a release script with appropriate configuration based on the project type
-func (p *GoLanguageProvider) GenerateReleaseScript(projectPath string, config map[string]interface{}) error {
-	// Check if GoReleaser is already configured
-	goreleaserPath := filepath.Join(projectPath, ".goreleaser.yaml")
-	if _, err := os.Stat(goreleaserPath); err == nil {
-		return fmt.Errorf(".goreleaser.yaml already exists")
+// RenderReleaseScript renders .goreleaser.yaml without writing it
+func (p *GoLanguageProvider) RenderReleaseScript(ctx context.Context, project *provider.Project) ([]plugin.GeneratedFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	projectConfig := releaseConfigFor(p.fsys, project)
+
+	templates := embedded.NewTemplateManager(project.Root)
+	manifest, err := plugin.LoadManifest(templates.Filesystem(), releaseTemplateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load GoReleaser templates: %w", err)
 	}
 
-	// Get project type from config
-	var projectType string
-	if config != nil {
-		if typeVal, ok := config["project_type"].(string); ok {
-			projectType = typeVal
+	data := releaseData{
+		ProjectName:   projectConfig.ProjectName,
+		ProjectType:   projectConfig.ProjectType,
+		ModulePath:    projectConfig.ModulePath,
+		Matrix:        newBuildMatrix(config.ResolveTargets(projectConfig.Targets, projectConfig.IgnoreTargets, p)),
+		ReleaseAssets: projectConfig.ReleaseAssets,
+	}
+	if projectConfig.ProjectType != "library" {
+		// Libraries do not build binaries
+		if data.Main, err = mainPackagePath(ctx, p.fsys, project); err != nil {
+			return nil, err
 		}
 	}
-	
+
+	files, err := plugin.RenderManifest(templates, manifest, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render GoReleaser configuration: %w", err)
+	}
+	return files, nil
+}
+
+// releaseConfigFor returns the configuration of a project, completing the
+// project name, module path and project type it leaves empty
+func releaseConfigFor(fsys vfs.FS, project *provider.Project) config.Config {
+	projectConfig := *project.Config
+
+	if projectConfig.ProjectName == "" {
+		projectConfig.ProjectName = filepath.Base(project.Root)
+	}
+	if projectConfig.ModulePath == "" {
+		projectConfig.ModulePath = modulePathFor(fsys, project.Root)
+	}
+
 	// If we couldn't determine the project type, check for common patterns
-	if projectType == "" {
-		// Check if there is a main.go file (indicates executable project)
-		mainPath := filepath.Join(projectPath, "main.go")
-		if _, err := os.Stat(mainPath); err == nil {
+	if projectConfig.ProjectType == "" {
+		if _, err := fsys.Stat(filepath.Join(project.Root, "main.go")); err == nil {
 			// Default to CLI if there's a main.go
-			projectType = "cli"
+			projectConfig.ProjectType = "cli"
 		} else {
 			// Default to library otherwise
-			projectType = "library"
+			projectConfig.ProjectType = "library"
 		}
 	}
-	
-	var goreleaserConfig string
-	
-	if projectType == "library" {
-		// For library projects, we don't build binaries
-		goreleaserConfig = `# GoReleaser configuration for Go library
-# Make sure to check the documentation at http://goreleaser.com
-version: 2
-
-before:
-  hooks:
-    - go mod tidy
-    - go test -v ./...
-
-# Explicitly disable builds for libraries
-builds:
-  - skip: true
-
-# Source distribution for libraries
-source:
-  enabled: true
-
-checksum:
-  name_template: 'checksums.txt'
-  
-changelog:
-  sort: asc
-  use: git
-  filters:
-    exclude:
-      - '^docs:'
-      - '^test:'
-      - '^ci:'
-      - Merge pull request
-      - Merge branch
-      
-sboms:
-  - artifacts: source
-`
-	} else {
-		// Find the main.go file for CLI/API projects
-		mainPath := "./main.go" // Default location
-		
-		// Check if main.go exists in the root directory
-		if _, err := os.Stat(filepath.Join(projectPath, "main.go")); err != nil {
-			// Main.go not in root, try to find it in common locations
-			possibleLocations := []string{
-				"cmd/main.go",
-				"cmd/app/main.go",
-				"cmd/server/main.go",
-				"cmd/cli/main.go",
-				"cmd/api/main.go",
-			}
-			
-			found := false
-			for _, loc := range possibleLocations {
-				if _, err := os.Stat(filepath.Join(projectPath, loc)); err == nil {
-					mainPath = "." + string(os.PathSeparator) + loc
-					found = true
-					break
-				}
-			}
-			
-			// If still not found, try more exhaustive search
-			if !found {
-				err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
-					if err != nil {
-						return err
-					}
-					if !info.IsDir() && info.Name() == "main.go" {
-						relPath, err := filepath.Rel(projectPath, path)
-						if err == nil {
-							mainPath = "." + string(os.PathSeparator) + relPath
-							found = true
-							return filepath.SkipAll
-						}
-					}
-					return nil
-				})
-				if err != nil {
-					fmt.Printf("Warning: Error searching for main.go: %v\n", err)
-				}
-			}
+
+	return projectConfig
+}
+
+// mainPackagePath returns the path of the main.go file of an executable project
+func mainPackagePath(ctx context.Context, fsys vfs.FS, project *provider.Project) (string, error) {
+	projectPath := project.Root
+	mainPath := "./main.go" // Default location
+
+	// Check if main.go exists in the root directory
+	if _, err := fsys.Stat(filepath.Join(projectPath, "main.go")); err == nil {
+		return mainPath, nil
+	}
+
+	// Main.go not in root, try to find it in common locations
+	possibleLocations := []string{
+		"cmd/main.go",
+		"cmd/app/main.go",
+		"cmd/server/main.go",
+		"cmd/cli/main.go",
+		"cmd/api/main.go",
+	}
+	for _, loc := range possibleLocations {
+		if _, err := fsys.Stat(filepath.Join(projectPath, loc)); err == nil {
+			return "./" + loc, nil
 		}
+	}
 
-		// For CLI/API/default projects, build binaries
-		goreleaserConfig = fmt.Sprintf(`# GoReleaser configuration for Go executable projects
-# Make sure to check the documentation at http://goreleaser.com
-version: 2
-
-before:
-  hooks:
-    - go mod tidy
-    - go test -v ./...
-
-builds:
-  - env:
-      - CGO_ENABLED=0
-    main: %s
-    goos:
-      - linux
-      - darwin
-      - windows
-    goarch:
-      - amd64
-      - arm64
-    ldflags:
-      - -s -w -X main.version={{.Version}} -X main.commit={{.Commit}} -X main.date={{.Date}} -X main.builtBy=goreleaser
-
-archives:
-  - format_overrides:
-    - goos: windows
-      format: zip
-    name_template: >-
-      {{ .ProjectName }}_
-      {{- title .Os }}_
-      {{- if eq .Arch "amd64" }}x86_64
-      {{- else if eq .Arch "386" }}i386
-      {{- else }}{{ .Arch }}{{ end }}
-      {{- if .Arm }}v{{ .Arm }}{{ end }}
-
-checksum:
-  name_template: 'checksums.txt'
-
-changelog:
-  sort: asc
-  use: git
-  filters:
-    exclude:
-      - '^docs:'
-      - '^test:'
-      - '^ci:'
-      - Merge pull request
-      - Merge branch
-
-sboms:
-  - artifacts: archive
-`, mainPath)
+	if _, err := fsys.Stat(projectPath); err != nil {
+		return mainPath, nil
 	}
 
-	// Write the GoReleaser configuration
-	if err := os.WriteFile(goreleaserPath, []byte(goreleaserConfig), 0644); err != nil {
-		return fmt.Errorf("failed to create GoReleaser configuration: %w", err)
+	// If still not found, try more exhaustive search, which stops when ctx is cancelled
+	err := vfs.WalkDir(fsys, projectPath, func(path string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && entry.Name() == "main.go" {
+			if relPath, err := filepath.Rel(projectPath, path); err == nil {
+				mainPath = "./" + filepath.ToSlash(relPath)
+				return filepath.SkipAll
+			}
+		}
+		return nil
+	})
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		project.Out.Warnf("Error searching for main.go: %v", err)
 	}
 
-	return nil
+	return mainPath, nil
 }
 
-// AddPlatform adds support for a new platform
-func (p *GoLanguageProvider) AddPlatform(projectPath, platform string) error {
-	// Validate platform
-	validPlatforms := []string{"linux", "darwin", "windows", "freebsd", "netbsd", "openbsd", "plan9", "solaris"}
-	if !contains(validPlatforms, platform) {
-		return fmt.Errorf("unsupported platform '%s' for Go language", platform)
-	}
-
-	// Check for .goreleaser.yaml
-	goreleaserPath := filepath.Join(projectPath, ".goreleaser.yaml")
-	if _, err := os.Stat(goreleaserPath); os.IsNotExist(err) {
-		// Load project configuration from .scotter.yaml
-		configPath := filepath.Join(projectPath, ".scotter.yaml")
-		configData, err := os.ReadFile(configPath)
-		
-		// If we can read the config file, use it to generate the release script
-		if err == nil {
-			config := make(map[string]interface{})
-			if err := yaml.Unmarshal(configData, &config); err == nil {
-				// Generate GoReleaser configuration with the project config
-				if err := p.GenerateReleaseScript(projectPath, config); err != nil {
-					return err
-				}
-			} else {
-				// Fall back to nil config if we can't parse the yaml
-				if err := p.GenerateReleaseScript(projectPath, nil); err != nil {
-					return err
-				}
-			}
-		} else {
-			// Fall back to nil config if we can't read the file
-			if err := p.GenerateReleaseScript(projectPath, nil); err != nil {
-				return err
+// modulePathFor returns the module path of a project, taken from its go.mod file
+func modulePathFor(fsys vfs.FS, projectPath string) string {
+	if data, err := fsys.ReadFile(filepath.Join(projectPath, "go.mod")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+				return strings.Trim(fields[1], `"`)
 			}
 		}
 	}
 
-	// For now, we'll just notify that the platform will be added in the GoReleaser configuration
-	// In a real implementation, this would modify the .goreleaser.yaml file
-	fmt.Printf("Platform '%s' will be added to GoReleaser configuration\n", platform)
+	return fmt.Sprintf("github.com/%s", filepath.Base(projectPath))
+}
 
-	return nil
+// ensureReleaseScript generates .goreleaser.yaml from the project configuration when it does not exist yet
+func (p *GoLanguageProvider) ensureReleaseScript(ctx context.Context, project *provider.Project) error {
+	goreleaserPath := filepath.Join(project.Root, goreleaserFile)
+	if _, err := p.fsys.Stat(goreleaserPath); !os.IsNotExist(err) {
+		return nil
+	}
+
+	return p.GenerateReleaseScript(ctx, project)
 }
 
-// AddReleaseAsset adds support for a new release asset type
-func (p *GoLanguageProvider) AddReleaseAsset(projectPath, assetType string) error {
-	// Validate asset type
-	validAssetTypes := []string{"checksum", "sbom", "archive"}
-	if !contains(validAssetTypes, assetType) {
-		return fmt.Errorf("unsupported release asset type '%s' for Go language", assetType)
-	}
-
-	// Check for .goreleaser.yaml
-	goreleaserPath := filepath.Join(projectPath, ".goreleaser.yaml")
-	if _, err := os.Stat(goreleaserPath); os.IsNotExist(err) {
-		// Load project configuration from .scotter.yaml
-		configPath := filepath.Join(projectPath, ".scotter.yaml")
-		configData, err := os.ReadFile(configPath)
-		
-		// If we can read the config file, use it to generate the release script
-		if err == nil {
-			config := make(map[string]interface{})
-			if err := yaml.Unmarshal(configData, &config); err == nil {
-				// Generate GoReleaser configuration with the project config
-				if err := p.GenerateReleaseScript(projectPath, config); err != nil {
-					return err
-				}
-			} else {
-				// Fall back to nil config if we can't parse the yaml
-				if err := p.GenerateReleaseScript(projectPath, nil); err != nil {
-					return err
-				}
-			}
-		} else {
-			// Fall back to nil config if we can't read the file
-			if err := p.GenerateReleaseScript(projectPath, nil); err != nil {
-				return err
-			}
+// SetTargets updates the build matrix of the GoReleaser configuration to the given targets
+func (p *GoLanguageProvider) SetTargets(ctx context.Context, project *provider.Project, targets []string) error {
+	for _, target := range targets {
+		if !p.IsSupportedTarget(target) {
+			return &scerrors.UnsupportedTargetError{Kind: "target", Value: target, Provider: p.Name()}
 		}
 	}
+	if err := p.ensureReleaseScript(ctx, project); err != nil {
+		return err
+	}
 
-	// For now, we'll just notify that the asset type will be added in the GoReleaser configuration
-	// In a real implementation, this would modify the .goreleaser.yaml file
-	fmt.Printf("Release asset type '%s' will be added to GoReleaser configuration\n", assetType)
+	return editGoReleaserConfig(p.fsys, project.Root, func(doc *yamledit.Document) error {
+		return setBuildMatrix(doc, newBuildMatrix(targets))
+	})
+}
 
-	return nil
+// AddReleaseAsset adds the GoReleaser section producing a release asset type
+func (p *GoLanguageProvider) AddReleaseAsset(ctx context.Context, project *provider.Project, assetType string) error {
+	if !p.IsSupportedReleaseAsset(assetType) {
+		return &scerrors.UnsupportedTargetError{Kind: "release asset type", Value: assetType, Provider: p.Name(), Supported: p.GetSupportedReleaseAssets()}
+	}
+	if err := p.ensureReleaseScript(ctx, project); err != nil {
+		return err
+	}
+
+	return editGoReleaserConfig(p.fsys, project.Root, func(doc *yamledit.Document) error {
+		return addAssetSection(doc, assetType)
+	})
+}
+
+// RemoveReleaseAsset drops the GoReleaser section producing a release asset type
+func (p *GoLanguageProvider) RemoveReleaseAsset(ctx context.Context, project *provider.Project, assetType string) error {
+	if !p.IsSupportedReleaseAsset(assetType) {
+		return &scerrors.UnsupportedTargetError{Kind: "release asset type", Value: assetType, Provider: p.Name(), Supported: p.GetSupportedReleaseAssets()}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return editGoReleaserConfig(p.fsys, project.Root, func(doc *yamledit.Document) error {
+		return removeAssetSection(doc, assetType)
+	})
 }
 
 // Helper function to check if a slice contains a string
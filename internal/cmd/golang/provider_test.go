@@ -0,0 +1,29 @@
+package golang
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/provider"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+)
+
+func TestReleaseAssetUnsupported(t *testing.T) {
+	p := NewGoLanguageProvider()
+	project := &provider.Project{Root: t.TempDir(), Config: &config.Config{ProjectType: "default", Language: "go"}}
+	ctx := context.Background()
+
+	for name, err := range map[string]error{
+		"AddReleaseAsset":    p.AddReleaseAsset(ctx, project, "wheel"),
+		"RemoveReleaseAsset": p.RemoveReleaseAsset(ctx, project, "wheel"),
+	} {
+		if !errors.Is(err, scerrors.ErrUnsupportedTarget) {
+			t.Errorf("%s() error = %v, want ErrUnsupportedTarget", name, err)
+		}
+		if code := scerrors.ExitCode(err); code != scerrors.ExitUnsupportedTarget {
+			t.Errorf("%s() exit code = %d, want %d", name, code, scerrors.ExitUnsupportedTarget)
+		}
+	}
+}
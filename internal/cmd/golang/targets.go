@@ -37,6 +37,86 @@ var SupportedGoArchitectures = []string{
 	"wasm",
 }
 
+// SupportedGoTargets contains every valid GOOS/GOARCH pair, as reported by
+// `go tool dist list`
+var SupportedGoTargets = []string{
+	"aix/ppc64",
+	"android/386",
+	"android/amd64",
+	"android/arm",
+	"android/arm64",
+	"darwin/amd64",
+	"darwin/arm64",
+	"dragonfly/amd64",
+	"freebsd/386",
+	"freebsd/amd64",
+	"freebsd/arm",
+	"freebsd/arm64",
+	"illumos/amd64",
+	"ios/amd64",
+	"ios/arm64",
+	"js/wasm",
+	"linux/386",
+	"linux/amd64",
+	"linux/arm",
+	"linux/arm64",
+	"linux/loong64",
+	"linux/mips",
+	"linux/mips64",
+	"linux/mips64le",
+	"linux/mipsle",
+	"linux/ppc64",
+	"linux/ppc64le",
+	"linux/riscv64",
+	"linux/s390x",
+	"netbsd/386",
+	"netbsd/amd64",
+	"netbsd/arm",
+	"netbsd/arm64",
+	"openbsd/386",
+	"openbsd/amd64",
+	"openbsd/arm",
+	"openbsd/arm64",
+	"openbsd/ppc64",
+	"openbsd/riscv64",
+	"plan9/386",
+	"plan9/amd64",
+	"plan9/arm",
+	"solaris/amd64",
+	"wasip1/wasm",
+	"windows/386",
+	"windows/amd64",
+	"windows/arm64",
+}
+
+// goVariantSetting is a GoReleaser build setting selecting the variant of
+// some architectures, such as the ARM version or the x86-64 microarchitecture level
+type goVariantSetting struct {
+	Key           string
+	Architectures []string
+	Values        []string
+	Default       string
+}
+
+// goVariantSettings contains the variant settings, in the order they are written
+// in GoReleaser builds (GOARM, GOAMD64, GOMIPS/GOMIPS64 and GO386 values)
+var goVariantSettings = []goVariantSetting{
+	{Key: "goarm", Architectures: []string{"arm"}, Values: []string{"5", "6", "7"}, Default: "6"},
+	{Key: "goamd64", Architectures: []string{"amd64"}, Values: []string{"v1", "v2", "v3", "v4"}, Default: "v1"},
+	{Key: "gomips", Architectures: []string{"mips", "mipsle", "mips64", "mips64le"}, Values: []string{"hardfloat", "softfloat"}, Default: "hardfloat"},
+	{Key: "go386", Architectures: []string{"386"}, Values: []string{"sse2", "softfloat"}, Default: "sse2"},
+}
+
+// variantSetting returns the variant setting of an architecture, if it has variants
+func variantSetting(arch string) (goVariantSetting, bool) {
+	for _, setting := range goVariantSettings {
+		if contains(setting.Architectures, arch) {
+			return setting, true
+		}
+	}
+	return goVariantSetting{}, false
+}
+
 // SupportedGoReleaseAssets contains all supported release asset types for Go projects
 var SupportedGoReleaseAssets = []string{
 	"checksum", // SHA-256 checksums for binaries
@@ -1,5 +1,7 @@
 package golang
 
+import "github.com/caezarr-oss/scotter/pkg/config"
+
 // IsSupportedPlatform checks if a platform is supported by Go
 func (p *GoLanguageProvider) IsSupportedPlatform(platform string) bool {
 	return contains(SupportedGoPlatforms, platform)
@@ -10,6 +12,21 @@ func (p *GoLanguageProvider) IsSupportedArchitecture(arch string) bool {
 	return contains(SupportedGoArchitectures, arch)
 }
 
+// IsSupportedTarget checks if an os/arch target is a valid pair for Go and
+// its optional variant, as in linux/arm/7, a valid variant of the architecture
+func (p *GoLanguageProvider) IsSupportedTarget(target string) bool {
+	t, err := config.ParseTarget(target)
+	if err != nil || !contains(SupportedGoTargets, t.Platform()) {
+		return false
+	}
+	if t.Variant == "" {
+		return true
+	}
+
+	setting, ok := variantSetting(t.Arch)
+	return ok && contains(setting.Values, t.Variant)
+}
+
 // IsSupportedReleaseAsset checks if a release asset type is supported for Go projects
 func (p *GoLanguageProvider) IsSupportedReleaseAsset(assetType string) bool {
 	return contains(SupportedGoReleaseAssets, assetType)
@@ -31,6 +48,27 @@ func (p *GoLanguageProvider) GetSupportedArchitectures() []string {
 	return architectures
 }
 
+// GetSupportedTargets returns all valid os/arch targets for Go
+func (p *GoLanguageProvider) GetSupportedTargets() []string {
+	// Return a copy to prevent modification of the original slice
+	targets := make([]string, len(SupportedGoTargets))
+	copy(targets, SupportedGoTargets)
+	return targets
+}
+
+// GetSupportedVariants returns the variants of a Go architecture, if any
+func (p *GoLanguageProvider) GetSupportedVariants(arch string) []string {
+	setting, ok := variantSetting(arch)
+	if !ok {
+		return nil
+	}
+
+	// Return a copy to prevent modification of the original slice
+	variants := make([]string, len(setting.Values))
+	copy(variants, setting.Values)
+	return variants
+}
+
 // GetSupportedReleaseAssets returns all supported release asset types for Go projects
 func (p *GoLanguageProvider) GetSupportedReleaseAssets() []string {
 	// Return a copy to prevent modification of the original slice
@@ -0,0 +1,90 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"a\n", []string{"a\n"}},
+		{"a\nb", []string{"a\n", "b"}},
+		{"a\n\nb\n", []string{"a\n", "\n", "b\n"}},
+	}
+	for _, tt := range tests {
+		if got := Lines(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lines(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	got := Compute([]string{"a", "b"}, []string{"b", "c"})
+	want := []Op{{Delete, "a"}, {Equal, "b"}, {Insert, "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compute() = %v, want %v", got, want)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name             string
+		oldName, newName string
+		oldText, newText string
+		want             string
+	}{
+		{
+			name:    "unchanged",
+			oldName: "a/f", newName: "b/f",
+			oldText: "a\nb\n", newText: "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "changed line",
+			oldName: "a/f", newName: "b/f",
+			oldText: "a\nb\nc\n", newText: "a\nB\nc\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:    "created file",
+			oldName: "/dev/null", newName: "b/f",
+			oldText: "", newText: "x\n",
+			want: "--- /dev/null\n+++ b/f\n@@ -0,0 +1,1 @@\n+x\n",
+		},
+		{
+			name:    "deleted file",
+			oldName: "a/f", newName: "/dev/null",
+			oldText: "x\n", newText: "",
+			want: "--- a/f\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-x\n",
+		},
+		{
+			name:    "missing trailing newline",
+			oldName: "a/f", newName: "b/f",
+			oldText: "a", newText: "a\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,1 +1,1 @@\n-a\n\\ No newline at end of file\n+a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified(tt.oldName, tt.newName, tt.oldText, tt.newText); got != tt.want {
+				t.Errorf("Unified() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedSplitsDistantChanges(t *testing.T) {
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	newText := "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"
+
+	got := Unified("a/f", "b/f", oldText, newText)
+	if hunks := strings.Count(got, "\n@@ "); hunks != 2 {
+		t.Errorf("Unified() has %d hunks, want 2:\n%s", hunks, got)
+	}
+}
@@ -0,0 +1,84 @@
+package diff
+
+import "strings"
+
+// Merge3 merges the changes made from base to ours and from base to theirs.
+// Where both sides changed the same lines differently, the result holds both
+// versions between conflict markers labelled with oursLabel and theirsLabel.
+// The number of conflicts is returned along with the merged text.
+func Merge3(base, ours, theirs, oursLabel, theirsLabel string) (string, int) {
+	baseLines, ourLines, theirLines := Lines(base), Lines(ours), Lines(theirs)
+	toOurs := matches(baseLines, ourLines)
+	toTheirs := matches(baseLines, theirLines)
+
+	var b strings.Builder
+	conflicts := 0
+	i, o, t := 0, 0, 0
+	for i < len(baseLines) || o < len(ourLines) || t < len(theirLines) {
+		// A base line kept at the current position on both sides is stable
+		if i < len(baseLines) && toOurs[i] == o && toTheirs[i] == t {
+			b.WriteString(baseLines[i])
+			i, o, t = i+1, o+1, t+1
+			continue
+		}
+
+		// Otherwise the chunk runs until the next base line kept on both sides
+		end := i
+		for end < len(baseLines) && (toOurs[end] < 0 || toTheirs[end] < 0) {
+			end++
+		}
+		oEnd, tEnd := len(ourLines), len(theirLines)
+		if end < len(baseLines) {
+			oEnd, tEnd = toOurs[end], toTheirs[end]
+		}
+
+		baseChunk := strings.Join(baseLines[i:end], "")
+		ourChunk := strings.Join(ourLines[o:oEnd], "")
+		theirChunk := strings.Join(theirLines[t:tEnd], "")
+		switch {
+		case ourChunk == baseChunk:
+			b.WriteString(theirChunk)
+		case theirChunk == baseChunk || theirChunk == ourChunk:
+			b.WriteString(ourChunk)
+		default:
+			conflicts++
+			b.WriteString("<<<<<<< " + oursLabel + "\n")
+			b.WriteString(withNewline(ourChunk))
+			b.WriteString("=======\n")
+			b.WriteString(withNewline(theirChunk))
+			b.WriteString(">>>>>>> " + theirsLabel + "\n")
+		}
+		i, o, t = end, oEnd, tEnd
+	}
+
+	return b.String(), conflicts
+}
+
+// matches maps every line of a to the index of the line of b it is kept as,
+// or -1 when it was deleted
+func matches(a, b []string) []int {
+	result := make([]int, len(a))
+	i, j := 0, 0
+	for _, op := range Compute(a, b) {
+		switch op.Kind {
+		case Equal:
+			result[i] = j
+			i++
+			j++
+		case Delete:
+			result[i] = -1
+			i++
+		case Insert:
+			j++
+		}
+	}
+	return result
+}
+
+// withNewline terminates a non-empty text with a newline
+func withNewline(text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		return text + "\n"
+	}
+	return text
+}
@@ -0,0 +1,94 @@
+package diff
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflicts          int
+	}{
+		{
+			name: "unchanged",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "ours only",
+			base: "a\nb\nc\n", ours: "a\nB\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "theirs only",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nB\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "both sides on different lines",
+			base: "a\nb\nc\nd\ne\n", ours: "A\nb\nc\nd\ne\n", theirs: "a\nb\nc\nd\nE\n",
+			want: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name: "identical edits",
+			base: "a\nb\nc\n", ours: "a\nB\nc\n", theirs: "a\nB\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "overlapping edits",
+			base: "a\nb\nc\n", ours: "a\nX\nc\n", theirs: "a\nY\nc\n",
+			want:      "a\n<<<<<<< current\nX\n=======\nY\n>>>>>>> upgrade\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "insertions at the same offset",
+			base: "a\nb\n", ours: "a\nX\nb\n", theirs: "a\nY\nb\n",
+			want:      "a\n<<<<<<< current\nX\n=======\nY\n>>>>>>> upgrade\nb\n",
+			conflicts: 1,
+		},
+		{
+			name: "identical insertions",
+			base: "a\nb\n", ours: "a\nX\nb\n", theirs: "a\nX\nb\n",
+			want: "a\nX\nb\n",
+		},
+		{
+			name: "insertions at different offsets",
+			base: "a\nb\nc\n", ours: "X\na\nb\nc\n", theirs: "a\nb\nc\nY\n",
+			want: "X\na\nb\nc\nY\n",
+		},
+		{
+			name: "deletion on one side",
+			base: "a\nb\nc\n", ours: "a\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nc\n",
+		},
+		{
+			name: "deletion against an edit",
+			base: "a\nb\nc\n", ours: "a\nc\n", theirs: "a\nB\nc\n",
+			want:      "a\n<<<<<<< current\n=======\nB\n>>>>>>> upgrade\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "missing trailing newline",
+			base: "a\nb", ours: "a\nb", theirs: "a\nb\nc",
+			want: "a\nb\nc",
+		},
+		{
+			name: "conflict without trailing newline",
+			base: "a\nb", ours: "a\nX", theirs: "a\nY",
+			want:      "a\n<<<<<<< current\nX\n=======\nY\n>>>>>>> upgrade\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3(tt.base, tt.ours, tt.theirs, "current", "upgrade")
+			if got != tt.want {
+				t.Errorf("Merge3() = %q, want %q", got, tt.want)
+			}
+			if conflicts != tt.conflicts {
+				t.Errorf("Merge3() conflicts = %d, want %d", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
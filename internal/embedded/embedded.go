@@ -2,67 +2,69 @@
 package embedded
 
 import (
-	"embed"
 	"io/fs"
 	"text/template"
-	"os"
 	"path/filepath"
 	"strings"
 	
+	"github.com/caezarr-oss/scotter/internal/templates"
+	"github.com/caezarr-oss/scotter/internal/txn"
 	"github.com/caezarr-oss/scotter/pkg/plugin"
 )
 
-//go:embed templates/placeholder.txt
-var templateFS embed.FS
+// templateFS is the template tree embedded from internal/templates
+var templateFS fs.FS = templates.FS
 
-// TemplateManager is the implementation of plugin.TemplateManager for embedded templates
-type TemplateManager struct{}
+// TemplateManager is the implementation of plugin.TemplateManager for embedded
+// templates, layered under the project, user and organization overrides
+type TemplateManager struct {
+	fsys *LayeredFS
+}
+
+// NewTemplateManager creates a new template manager using the default
+// layers of a project directory
+func NewTemplateManager(projectPath string) *TemplateManager {
+	return NewLayeredTemplateManager(DefaultLayers(projectPath)...)
+}
 
-// NewTemplateManager creates a new embedded template manager
-func NewTemplateManager() *TemplateManager {
-	return &TemplateManager{}
+// NewLayeredTemplateManager creates a template manager over explicit layers
+func NewLayeredTemplateManager(layers ...Layer) *TemplateManager {
+	return &TemplateManager{fsys: NewLayeredFS(layers...)}
 }
 
-// Filesystem returns the embedded filesystem
+// Filesystem returns the layered template filesystem
 func (m *TemplateManager) Filesystem() fs.FS {
-	return templateFS
+	return m.fsys
 }
 
-// RenderToFile renders a template to a file
-func (m *TemplateManager) RenderToFile(templatePath, targetPath string, data interface{}) error {
-	// Ensure the target directory exists
-	dir := filepath.Dir(targetPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
+// Which returns the layer providing a template
+func (m *TemplateManager) Which(templatePath string) (Layer, error) {
+	return m.fsys.Which(templatePath)
+}
 
-	// Read template from embedded filesystem
-	templateContent, err := fs.ReadFile(templateFS, templatePath)
-	if err != nil {
-		return err
-	}
+// Sources returns every layer providing a template, highest priority first
+func (m *TemplateManager) Sources(templatePath string) []Layer {
+	return m.fsys.Sources(templatePath)
+}
 
-	// Parse and execute the template
-	tmpl, err := template.New(filepath.Base(templatePath)).Parse(string(templateContent))
+// RenderToFile renders a template to a file
+func (m *TemplateManager) RenderToFile(templatePath, targetPath string, data interface{}) error {
+	content, err := m.RenderToString(templatePath, data)
 	if err != nil {
 		return err
 	}
 
-	// Create target file
-	file, err := os.Create(targetPath)
-	if err != nil {
+	// Ensure the target directory exists
+	if err := txn.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 		return err
 	}
-	defer file.Close()
-
-	// Execute the template to the file
-	return tmpl.Execute(file, data)
+	return txn.WriteFile(targetPath, []byte(content), 0644)
 }
 
 // RenderToString renders a template as a string
 func (m *TemplateManager) RenderToString(templatePath string, data interface{}) (string, error) {
-	// Read template from embedded filesystem
-	templateContent, err := fs.ReadFile(templateFS, templatePath)
+	// Read template from the layered filesystem
+	templateContent, err := fs.ReadFile(m.fsys, templatePath)
 	if err != nil {
 		return "", err
 	}
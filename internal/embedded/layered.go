@@ -0,0 +1,137 @@
+package embedded
+
+import (
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+)
+
+// Layer is a single template source of a LayeredFS
+type Layer struct {
+	// Name identifies the layer (project, user, org or embedded)
+	Name string
+
+	// Root is the directory backing the layer, empty for embedded templates
+	Root string
+
+	FS fs.FS
+}
+
+// LayeredFS stacks template sources; a file in a higher layer shadows
+// the same path in every layer below it
+type LayeredFS struct {
+	layers []Layer
+}
+
+// NewLayeredFS creates a layered filesystem, layers being ordered from highest priority down
+func NewLayeredFS(layers ...Layer) *LayeredFS {
+	return &LayeredFS{layers: layers}
+}
+
+// DefaultLayers returns the standard template layers: project-local
+// .scotter/templates, the user template directory, the organization
+// directories and finally the embedded templates
+func DefaultLayers(projectPath string) []Layer {
+	var layers []Layer
+
+	addDir := func(name, dir string) {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			layers = append(layers, Layer{Name: name, Root: dir, FS: os.DirFS(dir)})
+		}
+	}
+
+	addDir("project", config.ProjectTemplateDir(projectPath))
+	if dir, err := config.UserTemplateDir(); err == nil {
+		addDir("user", dir)
+	}
+	for _, dir := range config.OrgTemplateDirs() {
+		addDir("org", dir)
+	}
+
+	return append(layers, Layer{Name: "embedded", FS: templateFS})
+}
+
+// Layers returns the layers from highest priority down
+func (l *LayeredFS) Layers() []Layer {
+	return l.layers
+}
+
+// Open opens the named file from the highest layer containing it
+func (l *LayeredFS) Open(name string) (fs.File, error) {
+	layer, err := l.Which(name)
+	if err != nil {
+		return nil, err
+	}
+	return layer.FS.Open(name)
+}
+
+// Stat returns file information from the highest layer containing the file
+func (l *LayeredFS) Stat(name string) (fs.FileInfo, error) {
+	layer, err := l.Which(name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(layer.FS, name)
+}
+
+// ReadDir merges the directory listings of every layer; entries of
+// higher layers take precedence over entries with the same name
+func (l *LayeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	seen := make(map[string]fs.DirEntry)
+	found := false
+	for _, layer := range l.layers {
+		entries, err := fs.ReadDir(layer.FS, name)
+		if err != nil {
+			continue
+		}
+		found = true
+		for _, entry := range entries {
+			if _, ok := seen[entry.Name()]; !ok {
+				seen[entry.Name()] = entry
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(seen))
+	for _, entry := range seen {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// Which returns the layer that provides the named file
+func (l *LayeredFS) Which(name string) (Layer, error) {
+	if !fs.ValidPath(name) {
+		return Layer{}, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, layer := range l.layers {
+		if _, err := fs.Stat(layer.FS, name); err == nil {
+			return layer, nil
+		}
+	}
+	return Layer{}, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Sources returns every layer containing the named file, highest priority first
+func (l *LayeredFS) Sources(name string) []Layer {
+	var layers []Layer
+	for _, layer := range l.layers {
+		if _, err := fs.Stat(layer.FS, name); err == nil {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// Ensure LayeredFS supports directory listing and stat
+var (
+	_ fs.ReadDirFS = (*LayeredFS)(nil)
+	_ fs.StatFS    = (*LayeredFS)(nil)
+)
@@ -0,0 +1,150 @@
+// Package lockfile records the files Scotter generated in a project
+package lockfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// File is the name of the lockfile in the project root
+	File = ".scotter.lock"
+
+	// BaseDir holds a copy of the last generated content of every file, the
+	// base of three-way merges on upgrade
+	BaseDir = ".scotter/base"
+)
+
+// Entry describes the last generated content of a file
+type Entry struct {
+	Template string `yaml:"template,omitempty"`
+	Version  string `yaml:"version,omitempty"`
+	SHA256   string `yaml:"sha256"`
+}
+
+// Lock maps the generated files of a project, by slash-separated path, to their entry
+type Lock struct {
+	Files map[string]Entry `yaml:"files"`
+
+	fsys  vfs.FS
+	path  string
+	bases map[string][]byte
+}
+
+// Load reads the lockfile of a project from fsys; a missing lockfile yields
+// an empty lock
+func Load(fsys vfs.FS, projectPath string) (*Lock, error) {
+	lock := &Lock{
+		Files: make(map[string]Entry),
+		fsys:  fsys,
+		path:  filepath.Join(projectPath, File),
+		bases: make(map[string][]byte),
+	}
+
+	data, err := fsys.ReadFile(lock.path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", File, err)
+	}
+	if lock.Files == nil {
+		lock.Files = make(map[string]Entry)
+	}
+	return lock, nil
+}
+
+// Save writes the lockfile and the base copies of the recorded files
+func (l *Lock) Save() error {
+	for path, content := range l.bases {
+		basePath := l.basePath(path)
+		if err := l.fsys.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+			return err
+		}
+		if err := l.fsys.WriteFile(basePath, content, 0644); err != nil {
+			return err
+		}
+	}
+	l.bases = make(map[string][]byte)
+
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return l.fsys.WriteFile(l.path, data, 0644)
+}
+
+// Base returns the last generated content of a file
+func (l *Lock) Base(path string) ([]byte, error) {
+	if content, ok := l.bases[path]; ok {
+		return content, nil
+	}
+	return l.fsys.ReadFile(l.basePath(path))
+}
+
+// basePath returns the path of the base copy of a file
+func (l *Lock) basePath(path string) string {
+	return filepath.Join(filepath.Dir(l.path), filepath.FromSlash(BaseDir), filepath.FromSlash(path))
+}
+
+// Record stores the template, version and content of a generated file
+func (l *Lock) Record(file plugin.GeneratedFile) {
+	l.Files[file.Path] = Entry{
+		Template: file.Template,
+		Version:  file.Version,
+		SHA256:   Hash(file.Content),
+	}
+	l.bases[file.Path] = file.Content
+}
+
+// Update stores the new content of a file Scotter edited in place, keeping
+// the template and version it was generated from
+func (l *Lock) Update(path string, content []byte) {
+	entry := l.Files[path]
+	entry.SHA256 = Hash(content)
+	l.Files[path] = entry
+	l.bases[path] = content
+}
+
+// Modified reports whether the content of a file differs from what was last
+// generated; files without an entry are never reported as modified
+func (l *Lock) Modified(path string, content []byte) bool {
+	entry, ok := l.Files[path]
+	return ok && entry.SHA256 != Hash(content)
+}
+
+// WriteFiles writes generated files in a project and records them in its lockfile
+func WriteFiles(fsys vfs.FS, projectPath string, files []plugin.GeneratedFile) error {
+	lock, err := Load(fsys, projectPath)
+	if err != nil {
+		return fmt.Errorf("unable to load lockfile: %w", err)
+	}
+
+	if err := plugin.WriteGeneratedFiles(fsys, projectPath, files); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		lock.Record(file)
+	}
+	if err := lock.Save(); err != nil {
+		return fmt.Errorf("unable to save lockfile: %w", err)
+	}
+	return nil
+}
+
+// Hash returns the hex-encoded SHA-256 of a content
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,384 @@
+// Package packs installs and tracks template packs shared outside of Scotter
+package packs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Dir is the directory of the user template store holding installed packs
+	Dir = "packs"
+
+	// MetadataFile is the optional file describing a pack at its root
+	MetadataFile = "pack.yaml"
+
+	// LockFile is the file recording installed packs in the user configuration directory
+	LockFile = "packs.lock"
+)
+
+// validName restricts pack names to a single, portable path element
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Pack describes an installed template pack
+type Pack struct {
+	Name        string    `yaml:"name"`
+	Source      string    `yaml:"source"`
+	Version     string    `yaml:"version,omitempty"`
+	Commit      string    `yaml:"commit,omitempty"`
+	InstalledAt time.Time `yaml:"installed_at"`
+}
+
+// Metadata is the content of a pack.yaml file
+type Metadata struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// lockfile is the on-disk format of packs.lock
+type lockfile struct {
+	Packs []Pack `yaml:"packs"`
+}
+
+// Store manages the packs installed in the user template directory
+type Store struct {
+	// Dir is the directory holding one sub-directory per pack
+	Dir string
+
+	// LockPath is the path of the lockfile recording installed packs
+	LockPath string
+}
+
+// NewStore creates a store rooted in the user configuration directory
+func NewStore() (*Store, error) {
+	configDir, err := config.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate user configuration directory: %w", err)
+	}
+	templateDir, err := config.UserTemplateDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate user template directory: %w", err)
+	}
+
+	return &Store{
+		Dir:      filepath.Join(templateDir, Dir),
+		LockPath: filepath.Join(configDir, LockFile),
+	}, nil
+}
+
+// Install fetches a pack from a local git repository, a git URL or a
+// .tar.gz archive and installs it, replacing any pack with the same name
+func (s *Store) Install(source, name string) (*Pack, error) {
+	// git would read such a source as an option
+	if strings.HasPrefix(source, "-") {
+		return nil, fmt.Errorf("invalid pack source '%s', use ./%s for a local path", source, source)
+	}
+
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create pack store: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(s.Dir, ".install-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// Record local sources with an absolute path so they can be reinstalled from anywhere
+	if _, err := os.Stat(source); err == nil {
+		if abs, err := filepath.Abs(source); err == nil {
+			source = abs
+		}
+	}
+	pack := &Pack{Source: source, InstalledAt: time.Now().UTC()}
+
+	root := filepath.Join(staging, "pack")
+	if isArchive(source) {
+		if err := extractArchive(source, root); err != nil {
+			return nil, fmt.Errorf("unable to unpack '%s': %w", source, err)
+		}
+		root = unwrapRoot(root)
+	} else {
+		commit, err := cloneRepository(source, root)
+		if err != nil {
+			return nil, err
+		}
+		pack.Commit = commit
+	}
+
+	// Pack metadata is optional, the name falls back to the source name
+	var metadata Metadata
+	if data, err := os.ReadFile(filepath.Join(root, MetadataFile)); err == nil {
+		if err := yaml.Unmarshal(data, &metadata); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", MetadataFile, err)
+		}
+	}
+	pack.Version = metadata.Version
+
+	switch {
+	case name != "":
+		pack.Name = name
+	case metadata.Name != "":
+		pack.Name = metadata.Name
+	default:
+		pack.Name = nameFromSource(source)
+	}
+	if !validName.MatchString(pack.Name) {
+		return nil, fmt.Errorf("invalid pack name '%s', use lowercase letters, digits, '.', '_' and '-'", pack.Name)
+	}
+
+	types, err := ProjectTypes(os.DirFS(root), ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("'%s' does not contain any template set (no */%s found)", source, plugin.ManifestFile)
+	}
+
+	target := filepath.Join(s.Dir, pack.Name)
+	if err := os.RemoveAll(target); err != nil {
+		return nil, fmt.Errorf("unable to replace existing pack '%s': %w", pack.Name, err)
+	}
+	if err := os.Rename(root, target); err != nil {
+		return nil, fmt.Errorf("unable to install pack '%s': %w", pack.Name, err)
+	}
+
+	if err := s.record(*pack); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// List returns the installed packs sorted by name
+func (s *Store) List() ([]Pack, error) {
+	lock, err := s.readLock()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(lock.Packs, func(i, j int) bool { return lock.Packs[i].Name < lock.Packs[j].Name })
+	return lock.Packs, nil
+}
+
+// Remove uninstalls a pack
+func (s *Store) Remove(name string) error {
+	lock, err := s.readLock()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i, p := range lock.Packs {
+		if p.Name == name {
+			lock.Packs = append(lock.Packs[:i], lock.Packs[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("pack '%s' is not installed", name)
+	}
+
+	if err := os.RemoveAll(filepath.Join(s.Dir, name)); err != nil {
+		return fmt.Errorf("unable to remove pack '%s': %w", name, err)
+	}
+	return s.writeLock(lock)
+}
+
+// Types returns the project types provided by an installed pack
+func (s *Store) Types(name string) ([]string, error) {
+	return ProjectTypes(os.DirFS(filepath.Join(s.Dir, name)), ".")
+}
+
+// ProjectTypes lists the template sets, directories holding a manifest, directly under dir
+func ProjectTypes(fsys fs.FS, dir string) ([]string, error) {
+	manifests, err := fs.Glob(fsys, path.Join(dir, "*", plugin.ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]string, 0, len(manifests))
+	for _, manifest := range manifests {
+		types = append(types, path.Base(path.Dir(manifest)))
+	}
+	sort.Strings(types)
+	return types, nil
+}
+
+// record adds or replaces a pack in the lockfile
+func (s *Store) record(pack Pack) error {
+	lock, err := s.readLock()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, p := range lock.Packs {
+		if p.Name == pack.Name {
+			lock.Packs[i] = pack
+			replaced = true
+		}
+	}
+	if !replaced {
+		lock.Packs = append(lock.Packs, pack)
+	}
+	return s.writeLock(lock)
+}
+
+// readLock loads the lockfile, a missing file meaning no pack is installed
+func (s *Store) readLock() (*lockfile, error) {
+	lock := &lockfile{}
+	data, err := os.ReadFile(s.LockPath)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("invalid pack lockfile %s: %w", s.LockPath, err)
+	}
+	return lock, nil
+}
+
+// writeLock saves the lockfile
+func (s *Store) writeLock(lock *lockfile) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.LockPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.LockPath, data, 0644)
+}
+
+// isArchive reports whether a source is a gzipped tarball
+func isArchive(source string) bool {
+	return strings.HasSuffix(source, ".tar.gz") || strings.HasSuffix(source, ".tgz")
+}
+
+// nameFromSource derives a pack name from the last element of its source
+func nameFromSource(source string) string {
+	name := strings.TrimRight(filepath.ToSlash(source), "/")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, suffix := range []string{".tar.gz", ".tgz", ".git"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return strings.ToLower(name)
+}
+
+// cloneRepository clones a git repository and returns the checked out commit
+func cloneRepository(source, target string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", &scerrors.ToolMissingError{Tool: "git", Purpose: fmt.Sprintf("install '%s'", source), Err: err}
+	}
+
+	clone := exec.Command("git", "clone", "--quiet", "--", source, target)
+	if output, err := clone.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unable to clone '%s': %w\n%s", source, err, output)
+	}
+
+	revParse := exec.Command("git", "rev-parse", "HEAD")
+	revParse.Dir = target
+	output, err := revParse.Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to read commit of '%s': %w", source, err)
+	}
+
+	// The history is not needed once the commit is recorded
+	if err := os.RemoveAll(filepath.Join(target, ".git")); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// extractArchive unpacks a .tar.gz archive into target
+func extractArchive(archive, target string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if name == "." {
+			continue
+		}
+		if !fs.ValidPath(name) {
+			return fmt.Errorf("archive entry '%s' escapes the pack directory", header.Name)
+		}
+		dest := filepath.Join(target, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, reader); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		default:
+			// Links and special files are never part of a template pack
+		}
+	}
+}
+
+// unwrapRoot descends into the single top-level directory many archives wrap their content in
+func unwrapRoot(root string) string {
+	entries, err := os.ReadDir(root)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return root
+	}
+	if _, err := os.Stat(filepath.Join(root, entries[0].Name(), plugin.ManifestFile)); err == nil {
+		// The single directory is itself a template set
+		return root
+	}
+	return filepath.Join(root, entries[0].Name())
+}
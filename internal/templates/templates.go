@@ -0,0 +1,10 @@
+// Package templates holds the template sets shipped with Scotter
+package templates
+
+import "embed"
+
+// FS contains the whole template tree, including dotfiles such as
+// .gitignore.tmpl
+//
+//go:embed all:golang all:github all:goreleaser
+var FS embed.FS
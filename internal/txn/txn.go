@@ -0,0 +1,284 @@
+// Package txn stages the file operations of a command so that they are
+// applied together or not at all. While a transaction is open, the writes
+// made through FS are recorded in an in-memory overlay that reads see;
+// Commit then moves every file into place and Rollback leaves the tree as it was.
+package txn
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+)
+
+var (
+	mu      sync.Mutex
+	current *Tx
+)
+
+// FS is the filesystem of the open transaction, or of the OS when there is none
+var FS vfs.FS = txnFS{}
+
+// Tx is a transaction over the filesystem
+type Tx struct {
+	overlay *vfs.Overlay
+
+	// steps are run once the files are in place
+	steps []Step
+
+	// undo restores the tree as the commit changes it, in reverse order
+	undo []func() error
+	done bool
+}
+
+// Step is an operation run after the commit, such as an external command
+type Step struct {
+	Description string
+	Run         func() error
+}
+
+// Begin opens a transaction receiving the file operations of FS until it is
+// committed or rolled back
+func Begin() *Tx {
+	tx := &Tx{overlay: vfs.NewOverlay(vfs.OS)}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = tx
+	return tx
+}
+
+// active returns the filesystem of the open transaction
+func active() vfs.FS {
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		return vfs.OS
+	}
+	return current.overlay
+}
+
+// WriteFile writes a file through FS
+func WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return FS.WriteFile(name, data, perm)
+}
+
+// MkdirAll creates a directory and its parents through FS
+func MkdirAll(name string, perm fs.FileMode) error {
+	return FS.MkdirAll(name, perm)
+}
+
+// ReadFile reads a file through FS
+func ReadFile(name string) ([]byte, error) {
+	return FS.ReadFile(name)
+}
+
+// Stat describes a file through FS
+func Stat(name string) (fs.FileInfo, error) {
+	return FS.Stat(name)
+}
+
+// AfterCommit runs a step once the files of the open transaction are in
+// place; a failing step rolls the transaction back. Without a transaction
+// the step runs right away.
+func AfterCommit(description string, run func() error) error {
+	mu.Lock()
+	tx := current
+	mu.Unlock()
+
+	if tx == nil {
+		return run()
+	}
+	tx.steps = append(tx.steps, Step{Description: description, Run: run})
+	return nil
+}
+
+// Changes returns the changes the transaction would make to the files
+func (tx *Tx) Changes() []vfs.Change {
+	return tx.overlay.Changes()
+}
+
+// Steps returns the steps the transaction would run after the commit
+func (tx *Tx) Steps() []Step {
+	return tx.steps
+}
+
+// Commit applies the staged operations: every file is written to a temporary
+// file next to its target, then renamed over it. When anything fails, the
+// changes already made are undone.
+func (tx *Tx) Commit() (err error) {
+	tx.close()
+	if tx.done {
+		return nil
+	}
+	tx.done = true
+
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.rollback(); rollbackErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+			}
+		}
+	}()
+
+	// Create the directories, shortest path first
+	for _, dir := range tx.overlay.Dirs() {
+		if err := tx.mkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	// Write every file next to its target first, so that a full disk or a
+	// permission problem is found before any file is replaced
+	changes := tx.overlay.Changes()
+	temps := make(map[string]string, len(changes))
+	defer func() {
+		for _, temp := range temps {
+			os.Remove(temp)
+		}
+	}()
+	for _, change := range changes {
+		if change.Kind == vfs.Deleted {
+			continue
+		}
+		if err := tx.mkdirAll(filepath.Dir(change.Path), 0755); err != nil {
+			return err
+		}
+		temp, err := writeTemp(change.Path, change.After, change.Perm)
+		if err != nil {
+			return fmt.Errorf("failed to stage %s: %w", change.Path, err)
+		}
+		temps[change.Path] = temp
+	}
+
+	// Move the files into place, keeping the previous content to undo
+	for _, change := range changes {
+		change := change
+		if change.Kind == vfs.Deleted {
+			if err := os.Remove(change.Path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+			}
+		} else {
+			if err := os.Rename(temps[change.Path], change.Path); err != nil {
+				return fmt.Errorf("failed to write %s: %w", change.Path, err)
+			}
+			delete(temps, change.Path)
+		}
+
+		if change.Kind == vfs.Created {
+			tx.undo = append(tx.undo, func() error { return os.Remove(change.Path) })
+			continue
+		}
+		tx.undo = append(tx.undo, func() error {
+			temp, err := writeTemp(change.Path, change.Before, 0644)
+			if err != nil {
+				return err
+			}
+			return os.Rename(temp, change.Path)
+		})
+	}
+
+	for _, step := range tx.steps {
+		if err := step.Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback discards the staged operations; it does nothing once the
+// transaction is committed
+func (tx *Tx) Rollback() error {
+	tx.close()
+	tx.done = true
+	return nil
+}
+
+// close detaches the transaction from FS
+func (tx *Tx) close() {
+	mu.Lock()
+	defer mu.Unlock()
+	if current == tx {
+		current = nil
+	}
+}
+
+// rollback undoes the changes of a failed commit, most recent first
+func (tx *Tx) rollback() error {
+	var failed error
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		if err := tx.undo[i](); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	tx.undo = nil
+	return failed
+}
+
+// mkdirAll creates a directory, recording how to remove the ones it creates
+func (tx *Tx) mkdirAll(dir string, perm fs.FileMode) error {
+	// Find the topmost missing directory, the one to remove on rollback
+	missing := ""
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = d
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if missing == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, perm); err != nil {
+		return err
+	}
+	// Everything below the directory was created by the transaction, including
+	// the files of the steps run after the commit
+	tx.undo = append(tx.undo, func() error { return os.RemoveAll(missing) })
+	return nil
+}
+
+// writeTemp writes the content of a file to a temporary file in its directory
+func writeTemp(path string, content []byte, perm fs.FileMode) (string, error) {
+	if info, err := os.Stat(path); err == nil {
+		// Existing files keep their mode, as os.WriteFile does
+		perm = info.Mode().Perm()
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return "", err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return "", err
+	}
+	if err := os.Chmod(temp.Name(), perm); err != nil {
+		os.Remove(temp.Name())
+		return "", err
+	}
+	return temp.Name(), nil
+}
+
+// txnFS routes the operations of FS to the open transaction
+type txnFS struct{}
+
+func (txnFS) ReadFile(name string) ([]byte, error) { return active().ReadFile(name) }
+func (txnFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return active().WriteFile(name, data, perm)
+}
+func (txnFS) MkdirAll(name string, perm fs.FileMode) error { return active().MkdirAll(name, perm) }
+func (txnFS) Stat(name string) (fs.FileInfo, error)        { return active().Stat(name) }
+func (txnFS) ReadDir(name string) ([]fs.DirEntry, error)   { return active().ReadDir(name) }
+func (txnFS) Remove(name string) error                     { return active().Remove(name) }
@@ -0,0 +1,214 @@
+package txn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readString reads a file of the test, failing it when the file is missing
+func readString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// assertMissing fails the test when a path exists
+func assertMissing(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s exists, want it removed (err = %v)", path, err)
+	}
+}
+
+// assertNoTemps fails the test when temporary files are left in dir
+func assertNoTemps(t *testing.T, dir string) {
+	t.Helper()
+	temps, err := filepath.Glob(filepath.Join(dir, ".*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(temps) > 0 {
+		t.Errorf("temporary files left behind: %q", temps)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Begin()
+	if err := WriteFile(existing, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "sub", "created.txt")
+	if err := MkdirAll(filepath.Dir(created), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(created, []byte("created"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ran := false
+	if err := AfterCommit("step", func() error {
+		// Steps see the files in place
+		ran = readString(t, created) == "created"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is written before the commit
+	if got := readString(t, existing); got != "old" {
+		t.Errorf("existing.txt = %q before the commit, want %q", got, "old")
+	}
+	assertMissing(t, created)
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if got := readString(t, existing); got != "new" {
+		t.Errorf("existing.txt = %q, want %q", got, "new")
+	}
+	if got := readString(t, created); got != "created" {
+		t.Errorf("created.txt = %q, want %q", got, "created")
+	}
+	if !ran {
+		t.Error("step did not run after the files were in place")
+	}
+	assertNoTemps(t, dir)
+}
+
+func TestCommitRestoresEarlierRenames(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(first, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file cannot be renamed over a directory holding files
+	blocked := filepath.Join(dir, "b")
+	if err := os.MkdirAll(filepath.Join(blocked, "child"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Begin()
+	if err := WriteFile(first, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(blocked, []byte("blocked"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "new", "c.txt")
+	if err := WriteFile(created, []byte("c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := tx.Commit()
+	if err == nil || !strings.Contains(err.Error(), "failed to write "+blocked) {
+		t.Fatalf("Commit() error = %v, want the failed rename of b", err)
+	}
+	if got := readString(t, first); got != "old" {
+		t.Errorf("a.txt = %q, want it restored to %q", got, "old")
+	}
+	assertMissing(t, filepath.Join(dir, "new"))
+	assertNoTemps(t, dir)
+}
+
+func TestCommitRestoresOnFailedStep(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	removed := filepath.Join(dir, "removed.txt")
+	if err := os.WriteFile(removed, []byte("removed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Begin()
+	if err := WriteFile(existing, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := FS.Remove(removed); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "created.txt")
+	if err := WriteFile(created, []byte("created"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("step failed")
+	if err := AfterCommit("failing step", func() error { return failure }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); !errors.Is(err, failure) {
+		t.Fatalf("Commit() error = %v, want %v", err, failure)
+	}
+	if got := readString(t, existing); got != "old" {
+		t.Errorf("existing.txt = %q, want it restored to %q", got, "old")
+	}
+	if got := readString(t, removed); got != "removed" {
+		t.Errorf("removed.txt = %q, want it restored", got)
+	}
+	assertMissing(t, created)
+}
+
+func TestRollback(t *testing.T) {
+	dir := t.TempDir()
+	created := filepath.Join(dir, "created.txt")
+
+	tx := Begin()
+	if err := WriteFile(created, []byte("created"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ran := false
+	if err := AfterCommit("step", func() error {
+		ran = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads see the staged file
+	if data, err := ReadFile(created); err != nil || string(data) != "created" {
+		t.Errorf("ReadFile() = %q, %v, want the staged content", data, err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if ran {
+		t.Error("step ran after a rollback")
+	}
+	assertMissing(t, created)
+
+	// A commit after the rollback does nothing
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Commit() after Rollback() error = %v", err)
+	}
+	if ran {
+		t.Error("step ran on a commit after the rollback")
+	}
+	assertMissing(t, created)
+}
+
+func TestAfterCommitWithoutTransaction(t *testing.T) {
+	ran := false
+	if err := AfterCommit("step", func() error {
+		ran = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !ran {
+		t.Error("step did not run right away without a transaction")
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"os"
 
 	"github.com/caezarr-oss/scotter/cmd"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-		os.Exit(1)
+		// The exit code tells scripts what failed, see package scerrors
+		os.Exit(scerrors.ExitCode(err))
 	}
 }
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/yamledit"
+	"gopkg.in/yaml.v3"
+)
+
+// Get returns the value of a dotted key, such as project_type,
+// extra_config.docker.registry or targets.0
+func (m *Manager) Get(key string) (interface{}, error) {
+	path, _, err := splitKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(m.Config)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	for _, element := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[element]; !ok {
+				return nil, fmt.Errorf("key '%s' is not set", key)
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(element)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("key '%s' is not set", key)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("key '%s' is not set", key)
+		}
+	}
+	return value, nil
+}
+
+// Set sets a dotted key to values in the configuration file: list keys take
+// every value, extra_config values are read as YAML, decimals excepted, and
+// other keys take a single value. The file is edited in place, keeping its comments and key
+// order, and is only written when the change introduces no problem.
+func (m *Manager) Set(key string, values []string, pluginLoader plugin.PluginLoader) error {
+	path, field, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	value, err := valueNode(key, field, len(path) > 1, values)
+	if err != nil {
+		return err
+	}
+
+	return m.edit(pluginLoader, func(doc *yamledit.Document) error {
+		parent := make([]interface{}, len(path)-1)
+		for i, element := range path[:len(path)-1] {
+			parent[i] = element
+		}
+		return doc.Set(parent, path[len(path)-1], value)
+	})
+}
+
+// Unset removes a dotted key from the configuration file, along with the
+// mappings it leaves empty
+func (m *Manager) Unset(key string, pluginLoader plugin.PluginLoader) error {
+	path, _, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+
+	return m.edit(pluginLoader, func(doc *yamledit.Document) error {
+		for len(path) > 0 {
+			parent := make([]interface{}, len(path)-1)
+			for i, element := range path[:len(path)-1] {
+				parent[i] = element
+			}
+			found, err := doc.Delete(parent, path[len(path)-1])
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("key '%s' is not set", key)
+			}
+
+			// Remove the parent as well when it is left empty
+			if node := doc.Get(parent...); len(parent) == 0 || node == nil || len(node.Content) > 0 {
+				return nil
+			}
+			path = path[:len(path)-1]
+		}
+		return nil
+	})
+}
+
+// edit applies an edit to the configuration file and writes it, unless the
+// edited configuration has problems the original did not have
+func (m *Manager) edit(pluginLoader plugin.PluginLoader, apply func(doc *yamledit.Document) error) error {
+	data, err := m.fs().ReadFile(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+	doc, err := yamledit.Parse(data)
+	if err != nil {
+		return fmt.Errorf("unable to parse %s: %w", DefaultConfigFile, err)
+	}
+	if err := apply(doc); err != nil {
+		return err
+	}
+	edited := doc.Bytes()
+
+	before, err := m.problemsIn(data, pluginLoader)
+	if err != nil {
+		return err
+	}
+	after, err := m.problemsIn(edited, pluginLoader)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(before))
+	for _, d := range before {
+		known[d.Message] = true
+	}
+	var introduced []Diagnostic
+	for _, d := range after {
+		if !known[d.Message] {
+			introduced = append(introduced, d)
+		}
+	}
+	if len(introduced) > 0 {
+		return &ValidationError{File: m.diagnostics().file, Diagnostics: introduced}
+	}
+
+	if err := m.fs().WriteFile(m.ConfigPath, edited, 0644); err != nil {
+		return err
+	}
+	if _, err := m.parse(edited); err != nil {
+		return err
+	}
+	m.applyEnv()
+	return nil
+}
+
+// problemsIn returns the problems of the content of a configuration file
+func (m *Manager) problemsIn(data []byte, pluginLoader plugin.PluginLoader) ([]Diagnostic, error) {
+	check := &Manager{ConfigPath: m.ConfigPath, Config: &Config{}, FS: m.FS}
+	_, err := check.parse(data)
+	var invalid *ValidationError
+	if err != nil && !(errors.As(err, &invalid) && len(check.problems) > 0) {
+		return nil, err
+	}
+
+	if err := check.Validate(pluginLoader); errors.As(err, &invalid) {
+		return invalid.Diagnostics, nil
+	}
+	return nil, nil
+}
+
+// splitKey splits a dotted key into its path, checking that it starts with a
+// setting of Config, and returns the field of that setting
+func splitKey(key string) ([]string, reflect.StructField, error) {
+	path := strings.Split(key, ".")
+	for _, element := range path {
+		if element == "" {
+			return nil, reflect.StructField{}, fmt.Errorf("invalid key '%s'", key)
+		}
+	}
+
+	fields := configFields()
+	field, ok := fields[path[0]]
+	if !ok {
+		var names []string
+		for name := range fields {
+			names = append(names, name)
+		}
+		if suggestion := closest(path[0], names); suggestion != "" {
+			return nil, field, fmt.Errorf("unknown key '%s', did you mean '%s'?", path[0], suggestion)
+		}
+		return nil, field, fmt.Errorf("unknown key '%s', expected one of %s", path[0], joinSorted(names))
+	}
+	return path, field, nil
+}
+
+// valueNode builds the YAML node of the values set to a key
+func valueNode(key string, field reflect.StructField, nested bool, values []string) (*yaml.Node, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no value given for '%s'", key)
+	}
+
+	kind := field.Type.Kind()
+	if nested && kind != reflect.Map {
+		return nil, fmt.Errorf("%s is not a mapping", strings.SplitN(key, ".", 2)[0])
+	}
+
+	switch kind {
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, value := range values {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+		}
+		return node, nil
+	case reflect.Map:
+		nodes := make([]*yaml.Node, len(values))
+		for i, value := range values {
+			var doc yaml.Node
+			if err := yaml.Unmarshal([]byte(value), &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Tag == "!!float" {
+				// Decimals such as versions are kept as written, 1.20 is not 1.2
+				nodes[i] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+				continue
+			}
+			nodes[i] = doc.Content[0]
+		}
+		if !nested && (len(nodes) > 1 || nodes[0].Kind != yaml.MappingNode) {
+			return nil, fmt.Errorf("%s must be a mapping", key)
+		}
+		if len(nodes) > 1 {
+			return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: nodes}, nil
+		}
+		return nodes[0], nil
+	}
+
+	if len(values) > 1 {
+		return nil, fmt.Errorf("%s takes a single value", key)
+	}
+	if kind == reflect.Int {
+		if _, err := strconv.Atoi(values[0]); err != nil {
+			return nil, fmt.Errorf("%s must be an integer", key)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: values[0]}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: values[0]}, nil
+}
@@ -3,10 +3,12 @@ package config
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,126 +19,201 @@ const (
 
 // Config represents the Scotter project configuration
 type Config struct {
-	ProjectName    string   `yaml:"project_name"`
-	ProjectType    string   `yaml:"project_type"`
-	Language       string   `yaml:"language"`
-	Platforms      []string `yaml:"platforms"`
-	Architectures  []string `yaml:"architectures"`
-	ReleaseAssets  []string `yaml:"release_assets"`
-	CIProvider     string   `yaml:"ci_provider"`
-	ExtraConfig    map[string]interface{} `yaml:"extra_config,omitempty"`
+	SchemaVersion  int      `yaml:"schema_version" doc:"Version of the configuration format"`
+	ProjectName    string   `yaml:"project_name" doc:"Name of the project"`
+	ModulePath     string   `yaml:"module_path,omitempty" doc:"Module path, such as github.com/acme/my-project"`
+	ProjectType    string   `yaml:"project_type" doc:"Project structure the project was scaffolded with"`
+	Language       string   `yaml:"language" doc:"Programming language of the project"`
+	Targets        []string `yaml:"targets,omitempty" doc:"Build targets as os/arch or os/arch/variant, * matching any name"`
+	IgnoreTargets  []string `yaml:"ignore_targets,omitempty" doc:"Patterns of the targets excluded from the build"`
+	ReleaseAssets  []string `yaml:"release_assets" doc:"Asset types published with each release"`
+	CIProvider     string   `yaml:"ci_provider" doc:"CI provider generating the workflows, empty for none"`
+	ExtraConfig    map[string]interface{} `yaml:"extra_config,omitempty" doc:"Additional settings passed to the templates"`
 }
 
 // Manager handles configuration operations
 type Manager struct {
 	ConfigPath string
 	Config     *Config
+
+	// FS is the filesystem the configuration file is read from and written
+	// to, the operating system by default
+	FS vfs.FS
+
+	// Header holds the comment lines written at the top of the file, such as
+	// the schema of the YAML language server
+	Header string
+
+	// root is the document loaded from ConfigPath, locating the problems
+	// found in the configuration
+	root *yaml.Node
+
+	// problems holds the structural problems found when loading the configuration
+	problems []Diagnostic
+
+	// overrides holds the settings replaced by environment variables on load
+	overrides []override
+}
+
+// override is a setting of the configuration file replaced by an environment
+// variable, with the value of the file
+type override struct {
+	Setting
+	file interface{}
 }
 
 // NewManager creates a new configuration manager
 func NewManager(projectPath string) *Manager {
 	return &Manager{
 		ConfigPath: filepath.Join(projectPath, DefaultConfigFile),
+		FS:         vfs.OS,
 		Config: &Config{
-			Platforms:     []string{},
-			Architectures: []string{},
+			SchemaVersion: CurrentSchemaVersion,
+			Targets:       []string{},
 			ReleaseAssets: []string{},
 			ExtraConfig:   make(map[string]interface{}),
 		},
 	}
 }
 
-// Load loads configuration from file
+// Load loads configuration from file, migrating it in memory when it was
+// written with an older schema version, and applies the SCOTTER_* environment
+// variables over it
 func (m *Manager) Load() error {
-	data, err := os.ReadFile(m.ConfigPath)
+	data, err := m.fs().ReadFile(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	applied, err := m.parse(data)
 	if err != nil {
 		return err
 	}
 
-	return yaml.Unmarshal(data, m.Config)
+	if len(applied) > 0 {
+		warnOutdated(m.ConfigPath, applied[0].Version-1)
+	}
+	m.applyEnv()
+	return nil
+}
+
+// Marshal returns the configuration as Save writes it, without the
+// environment overrides
+func (m *Manager) Marshal() ([]byte, error) {
+	cfg := m.fileConfig()
+	data, err := yaml.Marshal(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(m.Header), data...), nil
 }
 
 // Save saves configuration to file
 func (m *Manager) Save() error {
-	data, err := yaml.Marshal(m.Config)
+	data, err := m.Marshal()
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(m.ConfigPath, data, 0644)
+	return m.fs().WriteFile(m.ConfigPath, data, 0644)
 }
 
-// AddPlatform adds a new platform if not already present
-func (m *Manager) AddPlatform(platform string, langProvider plugin.LanguageProvider) error {
+// fs returns the filesystem of the configuration file
+func (m *Manager) fs() vfs.FS {
+	if m.FS == nil {
+		return vfs.OS
+	}
+	return m.FS
+}
+
+// AddPlatform adds a platform for every architecture already targeted
+func (m *Manager) AddPlatform(platform string, langProvider plugin.LanguageSupport) error {
 	// Validate that the platform is supported by the language provider
 	if !langProvider.IsSupportedPlatform(platform) {
-		return fmt.Errorf("platform '%s' is not supported by %s", platform, langProvider.Name())
+		return &scerrors.UnsupportedTargetError{Kind: "platform", Value: platform, Provider: langProvider.Name()}
 	}
 
 	// Check if platform already exists
-	for _, p := range m.Config.Platforms {
-		if p == platform {
-			return fmt.Errorf("platform '%s' already exists", platform)
+	if containsString(m.targetElements(false), platform) {
+		return &scerrors.AlreadyExistsError{Kind: "platform", Name: platform, Path: m.ConfigPath}
+	}
+
+	architectures := m.targetElements(true)
+	if len(architectures) == 0 {
+		architectures = defaultArchitectures
+	}
+
+	added := false
+	for _, arch := range architectures {
+		if target := platform + "/" + arch; langProvider.IsSupportedTarget(target) {
+			m.addTargetOnce(target)
+			added = true
 		}
 	}
-	
-	m.Config.Platforms = append(m.Config.Platforms, platform)
+	if !added {
+		return fmt.Errorf("platform '%s' supports none of the architectures %s", platform, strings.Join(architectures, ", "))
+	}
 	return nil
 }
 
-// RemovePlatform removes a platform
+// RemovePlatform removes every target of a platform
 func (m *Manager) RemovePlatform(platform string) error {
-	for i, p := range m.Config.Platforms {
-		if p == platform {
-			m.Config.Platforms = append(m.Config.Platforms[:i], m.Config.Platforms[i+1:]...)
-			return nil
-		}
+	if !m.removeTargets(platform, false) {
+		return fmt.Errorf("platform '%s' not found", platform)
 	}
-	
-	return fmt.Errorf("platform '%s' not found", platform)
+	return nil
 }
 
-// AddArchitecture adds a new architecture if not already present
-func (m *Manager) AddArchitecture(arch string, langProvider plugin.LanguageProvider) error {
+// AddArchitecture adds an architecture for every platform already targeted
+func (m *Manager) AddArchitecture(arch string, langProvider plugin.LanguageSupport) error {
 	// Validate that the architecture is supported by the language provider
 	if !langProvider.IsSupportedArchitecture(arch) {
-		return fmt.Errorf("architecture '%s' is not supported by %s", arch, langProvider.Name())
+		return &scerrors.UnsupportedTargetError{Kind: "architecture", Value: arch, Provider: langProvider.Name()}
 	}
 
 	// Check if architecture already exists
-	for _, a := range m.Config.Architectures {
-		if a == arch {
-			return fmt.Errorf("architecture '%s' already exists", arch)
+	if containsString(m.targetElements(true), arch) {
+		return &scerrors.AlreadyExistsError{Kind: "architecture", Name: arch, Path: m.ConfigPath}
+	}
+
+	platforms := m.targetElements(false)
+	if len(platforms) == 0 {
+		platforms = defaultPlatforms
+	}
+
+	added := false
+	for _, platform := range platforms {
+		if target := platform + "/" + arch; langProvider.IsSupportedTarget(target) {
+			m.addTargetOnce(target)
+			added = true
 		}
 	}
-	
-	m.Config.Architectures = append(m.Config.Architectures, arch)
+	if !added {
+		return fmt.Errorf("architecture '%s' is supported by none of the platforms %s", arch, strings.Join(platforms, ", "))
+	}
 	return nil
 }
 
-// RemoveArchitecture removes an architecture
+// RemoveArchitecture removes every target of an architecture
 func (m *Manager) RemoveArchitecture(arch string) error {
-	for i, a := range m.Config.Architectures {
-		if a == arch {
-			m.Config.Architectures = append(m.Config.Architectures[:i], m.Config.Architectures[i+1:]...)
-			return nil
-		}
+	if !m.removeTargets(arch, true) {
+		return fmt.Errorf("architecture '%s' not found", arch)
 	}
-	
-	return fmt.Errorf("architecture '%s' not found", arch)
+	return nil
 }
 
 // AddReleaseAsset adds a new release asset type if not already present
-func (m *Manager) AddReleaseAsset(assetType string, langProvider plugin.LanguageProvider) error {
+func (m *Manager) AddReleaseAsset(assetType string, langProvider plugin.LanguageSupport) error {
 	// Validate that the asset type is supported by the language provider
 	if !langProvider.IsSupportedReleaseAsset(assetType) {
-		return fmt.Errorf("release asset type '%s' is not supported by %s", assetType, langProvider.Name())
+		return &scerrors.UnsupportedTargetError{Kind: "release asset type", Value: assetType, Provider: langProvider.Name(), Supported: langProvider.GetSupportedReleaseAssets()}
 	}
 
 	// Check if asset type already exists
 	for _, a := range m.Config.ReleaseAssets {
 		if a == assetType {
-			return fmt.Errorf("release asset type '%s' already exists", assetType)
+			return &scerrors.AlreadyExistsError{Kind: "release asset type", Name: assetType, Path: m.ConfigPath}
 		}
 	}
 	
@@ -166,3 +243,15 @@ func (m *Manager) GetExtraConfig(key string) (interface{}, bool) {
 	value, ok := m.Config.ExtraConfig[key]
 	return value, ok
 }
+
+// leadingComments returns the comment lines at the top of a file
+func leadingComments(data []byte) string {
+	var header strings.Builder
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			break
+		}
+		header.WriteString(strings.TrimRight(line, "\r\n") + "\n")
+	}
+	return header.String()
+}
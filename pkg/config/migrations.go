@@ -0,0 +1,306 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/caezarr-oss/scotter/pkg/yamledit"
+	"gopkg.in/yaml.v3"
+)
+
+// Migration upgrades a configuration document to its schema version from the
+// version right before it, editing the document in place
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(doc *yamledit.Document) error
+}
+
+// migrations is the registry of schema migrations, in version order; a file
+// without schema_version is at version 0
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "convert the platforms and architectures lists into os/arch targets",
+		Apply:       migrateTargets,
+	},
+	{
+		Version:     2,
+		Description: "move the module_path and ci_provider settings out of extra_config",
+		Apply:       migrateExtraConfig,
+	},
+}
+
+// CurrentSchemaVersion is the schema version of the configurations written by this version
+var CurrentSchemaVersion = migrations[len(migrations)-1].Version
+
+// warned holds the configuration files already reported as outdated
+var warned sync.Map
+
+// Warnf reports the warnings of the package, such as an outdated schema
+// version; they are printed to the standard error by default
+var Warnf = func(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
+
+// Migrate upgrades the configuration file to the current schema version and
+// returns the migrations applied. The file is edited in place, keeping its
+// comments and key order, and is only written when a migration applies.
+func (m *Manager) Migrate() ([]Migration, error) {
+	data, err := m.fs().ReadFile(m.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	migrated, applied, err := m.migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.decode(migrated); err != nil {
+		return nil, err
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+	if err := m.fs().WriteFile(m.ConfigPath, migrated, 0644); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// parse decodes the content of a configuration file, migrating it in memory
+// to the current schema version
+func (m *Manager) parse(data []byte) ([]Migration, error) {
+	migrated, applied, err := m.migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.decode(migrated); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// migrate upgrades the content of a configuration file to the current schema
+// version, returning the upgraded content and the migrations applied
+func (m *Manager) migrate(data []byte) ([]byte, []Migration, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, err
+	}
+	m.root = &root
+	m.Header = leadingComments(data)
+
+	var doc map[string]interface{}
+	if err := root.Decode(&doc); err != nil {
+		d := m.diagnostics()
+		d.add(nil, false, "the configuration must be a mapping of keys to values")
+		return nil, nil, d.err()
+	}
+
+	version, err := schemaVersion(doc)
+	if err != nil {
+		d := m.diagnostics()
+		d.add([]interface{}{"schema_version"}, false, "%s", err)
+		return nil, nil, d.err()
+	}
+	if version > CurrentSchemaVersion {
+		return nil, nil, fmt.Errorf("%s uses schema version %d, newer than the version %d this scotter supports, upgrade scotter to use it",
+			DefaultConfigFile, version, CurrentSchemaVersion)
+	}
+	if version == CurrentSchemaVersion {
+		return data, nil, nil
+	}
+
+	edited, err := yamledit.Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var applied []Migration
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			continue
+		}
+		if err := migration.Apply(edited); err != nil {
+			return nil, nil, fmt.Errorf("unable to migrate %s to schema version %d: %w", DefaultConfigFile, migration.Version, err)
+		}
+		applied = append(applied, migration)
+	}
+
+	current := strconv.Itoa(CurrentSchemaVersion)
+	if _, ok := doc["schema_version"]; ok {
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: current}
+		if err := edited.Set(nil, "schema_version", value); err != nil {
+			return nil, nil, err
+		}
+		return edited.Bytes(), applied, nil
+	}
+
+	// Write the version first, below the comments heading the file
+	migrated := edited.Bytes()
+	header := leadingComments(migrated)
+	return []byte(header + "schema_version: " + current + "\n" + string(migrated[len(header):])), applied, nil
+}
+
+// decode checks the structure of a configuration document, reporting the
+// problems at their position in the loaded file, and decodes it
+func (m *Manager) decode(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+
+	d := m.diagnostics()
+	checkStructure(&root, d)
+	if err := d.err(); err != nil {
+		// Decode what can be, so Validate can report the other problems too
+		_ = yaml.Unmarshal(data, m.Config)
+		m.problems = d.list
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(m.Config); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// schemaVersion returns the schema_version of a configuration document
+func schemaVersion(doc map[string]interface{}) (int, error) {
+	value, ok := doc["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+
+	version, ok := value.(int)
+	if !ok || version < 0 {
+		return 0, fmt.Errorf("invalid schema_version '%v', expected a non-negative integer", value)
+	}
+	return version, nil
+}
+
+// warnOutdated reports once that a configuration file uses an older schema version
+func warnOutdated(configPath string, version int) {
+	if _, loaded := warned.LoadOrStore(configPath, true); loaded {
+		return
+	}
+	Warnf("%s uses schema version %d, run 'scotter config migrate' to upgrade it to version %d",
+		DefaultConfigFile, version, CurrentSchemaVersion)
+}
+
+// migrateTargets converts the former platforms and architectures lists into
+// os/arch targets; platform entries already written as os/arch are kept as is
+func migrateTargets(doc *yamledit.Document) error {
+	targets, err := stringList(doc, "targets")
+	if err != nil {
+		return err
+	}
+	platformList, err := stringList(doc, "platforms")
+	if err != nil {
+		return err
+	}
+	architectures, err := stringList(doc, "architectures")
+	if err != nil {
+		return err
+	}
+	if len(platformList) == 0 && len(architectures) == 0 {
+		return nil
+	}
+
+	addTarget := func(target string) {
+		if !containsString(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+
+	var platforms []string
+	for _, platform := range platformList {
+		if strings.Contains(platform, "/") {
+			addTarget(platform)
+			continue
+		}
+		platforms = append(platforms, platform)
+	}
+
+	if len(platforms) > 0 && len(architectures) == 0 {
+		architectures = defaultArchitectures
+	}
+	if len(architectures) > 0 && len(platforms) == 0 && len(targets) == 0 {
+		platforms = defaultPlatforms
+	}
+	for _, platform := range platforms {
+		for _, arch := range architectures {
+			addTarget(platform + "/" + arch)
+		}
+	}
+
+	for _, key := range []string{"platforms", "architectures"} {
+		if _, err := doc.Delete(nil, key); err != nil {
+			return err
+		}
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, target := range targets {
+		list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: target})
+	}
+	return doc.Set(nil, "targets", list)
+}
+
+// migrateExtraConfig moves the extra_config keys that became top-level
+// settings to the top level, where they used to be overridden
+func migrateExtraConfig(doc *yamledit.Document) error {
+	extra := doc.Get("extra_config")
+	if extra == nil || extra.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for _, key := range []string{"module_path", "ci_provider"} {
+		value := doc.Get("extra_config", key)
+		if value == nil {
+			continue
+		}
+		if value.Tag != "!!null" && !(value.Kind == yaml.ScalarNode && value.Value == "") {
+			if err := doc.Set(nil, key, value); err != nil {
+				return err
+			}
+		}
+		if _, err := doc.Delete([]interface{}{"extra_config"}, key); err != nil {
+			return err
+		}
+	}
+
+	// A mapping left without keys is read back as null
+	if extra := doc.Get("extra_config"); extra.Tag == "!!null" || len(extra.Content) == 0 {
+		_, err := doc.Delete(nil, "extra_config")
+		return err
+	}
+	return nil
+}
+
+// stringList returns the list of strings held by a key of a document
+func stringList(doc *yamledit.Document, key string) ([]string, error) {
+	value := doc.Get(key)
+	if value == nil || value.Tag == "!!null" {
+		return nil, nil
+	}
+
+	if value.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s must be a list", key)
+	}
+	list := make([]string, 0, len(value.Content))
+	for _, item := range value.Content {
+		// Unquoted names such as 386 are read as integers
+		if item.Kind != yaml.ScalarNode || (item.Tag != "!!str" && item.Tag != "!!int") {
+			return nil, fmt.Errorf("%s must only hold strings, found '%s'", key, item.Value)
+		}
+		list = append(list, item.Value)
+	}
+	return list, nil
+}
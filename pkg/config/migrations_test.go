@@ -0,0 +1,275 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+)
+
+func TestMigrateTargets(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "platforms and architectures",
+			in: `schema_version: 0
+project_name: demo # the name
+platforms:
+  - linux
+  - darwin
+architectures:
+  - amd64
+  - arm64
+`,
+			want: `schema_version: 2
+project_name: demo # the name
+
+targets:
+  - linux/amd64
+  - linux/arm64
+  - darwin/amd64
+  - darwin/arm64
+`,
+		},
+		{
+			name: "platforms only",
+			in: `project_name: demo
+platforms: [windows]
+`,
+			want: `schema_version: 2
+project_name: demo
+
+targets:
+  - windows/amd64
+  - windows/arm64
+`,
+		},
+		{
+			name: "architectures only",
+			in: `project_name: demo
+architectures: [386]
+`,
+			want: `schema_version: 2
+project_name: demo
+
+targets:
+  - linux/386
+  - darwin/386
+  - windows/386
+`,
+		},
+		{
+			name: "platforms written as targets",
+			in: `# Demo project
+project_name: demo
+targets:
+  - linux/amd64
+platforms:
+  - linux/amd64
+  - freebsd/amd64
+`,
+			want: `# Demo project
+schema_version: 2
+project_name: demo
+targets:
+  - linux/amd64
+  - freebsd/amd64
+`,
+		},
+		{
+			name: "nothing to convert",
+			in: `project_name: demo
+# Build targets
+targets:
+  - linux/amd64
+`,
+			want: `schema_version: 2
+project_name: demo
+# Build targets
+targets:
+  - linux/amd64
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(t.TempDir())
+			got, _, err := m.migrate([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("migrate() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("migrate() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateExtraConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "moved to the top level",
+			in: `schema_version: 1
+project_name: demo
+extra_config:
+  # Where the module lives
+  module_path: github.com/acme/demo
+  ci_provider: github # the CI
+  go_version: "1.22"
+`,
+			want: `schema_version: 2
+project_name: demo
+extra_config:
+  go_version: "1.22"
+
+module_path: github.com/acme/demo
+
+ci_provider: github # the CI
+`,
+		},
+		{
+			name: "overriding the top level",
+			in: `schema_version: 1
+ci_provider: "" # none yet
+extra_config:
+  ci_provider: github
+`,
+			want: `schema_version: 2
+ci_provider: github # none yet
+`,
+		},
+		{
+			name: "empty values dropped",
+			in: `schema_version: 1
+module_path: github.com/acme/demo
+extra_config:
+  module_path: ""
+  ci_provider:
+  docker:
+    registry: ghcr.io
+`,
+			want: `schema_version: 2
+module_path: github.com/acme/demo
+extra_config:
+  docker:
+    registry: ghcr.io
+`,
+		},
+		{
+			name: "without extra_config",
+			in: `schema_version: 1
+project_name: demo
+`,
+			want: `schema_version: 2
+project_name: demo
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(t.TempDir())
+			got, _, err := m.migrate([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("migrate() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("migrate() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultConfigFile)
+	in := `# Demo project
+project_name: demo
+project_type: default
+language: go
+platforms: [linux]
+architectures: [amd64]
+release_assets: []
+ci_provider: "" # no CI
+`
+	if err := os.WriteFile(path, []byte(in), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(dir)
+	applied, err := m.Migrate()
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != 2 {
+		t.Errorf("Migrate() applied %d migrations, want 2", len(applied))
+	}
+	if want := []string{"linux/amd64"}; !reflect.DeepEqual(m.Config.Targets, want) {
+		t.Errorf("Targets = %q, want %q", m.Config.Targets, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Demo project
+schema_version: 2
+project_name: demo
+project_type: default
+language: go
+release_assets: []
+ci_provider: "" # no CI
+
+targets:
+  - linux/amd64
+`
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+
+	// A second run finds nothing to migrate and leaves the file alone
+	applied, err = NewManager(dir).Migrate()
+	if err != nil || len(applied) != 0 {
+		t.Errorf("Migrate() = %v, %v, want nothing applied", applied, err)
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if _, _, err := m.migrate([]byte("schema_version: 99\n")); err == nil {
+		t.Error("migrate() error = nil, want the newer version refused")
+	}
+}
+
+func TestMigrateThroughFS(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultConfigFile)
+	in := "project_name: demo\nplatforms: [linux]\n"
+	if err := os.WriteFile(path, []byte(in), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	overlay := vfs.NewOverlay(vfs.OS)
+	m := NewManager(dir)
+	m.FS = overlay
+	if _, err := m.Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != in {
+		t.Errorf("file on disk =\n%s\nwant it unchanged", data)
+	}
+	if changes := overlay.Changes(); len(changes) != 1 || changes[0].Path != path {
+		t.Errorf("overlay changes = %+v, want %s", changes, path)
+	}
+}
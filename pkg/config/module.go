@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultModulePath derives the module path of a project from, in order,
+// the origin remote of its git repository, the module prefix and finally
+// github.com/<project-name>
+func DefaultModulePath(projectPath, projectName, modulePrefix string) string {
+	if modulePath := modulePathFromGit(projectPath); modulePath != "" {
+		return modulePath
+	}
+
+	name := filepath.Base(projectName)
+	if modulePrefix != "" {
+		return path.Join(strings.TrimSuffix(modulePrefix, "/"), name)
+	}
+
+	return fmt.Sprintf("github.com/%s", name)
+}
+
+// modulePathFromGit returns the module path matching the origin remote of
+// the repository rooted at projectPath, if any
+func modulePathFromGit(projectPath string) string {
+	if _, err := os.Stat(filepath.Join(projectPath, ".git")); err != nil {
+		return ""
+	}
+
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = projectPath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return ModulePathFromRemote(strings.TrimSpace(string(output)))
+}
+
+// ModulePathFromRemote converts a git remote URL such as
+// git@gitlab.corp:team/name.git or https://github.com/org/name into a module path
+func ModulePathFromRemote(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+
+	if i := strings.Index(remote, "://"); i >= 0 {
+		// ssh://git@host:22/team/name, https://user@host/team/name
+		remote = remote[i+3:]
+		if at := strings.LastIndex(remote, "@"); at >= 0 {
+			remote = remote[at+1:]
+		}
+		host, rest, _ := strings.Cut(remote, "/")
+		if colon := strings.Index(host, ":"); colon >= 0 {
+			host = host[:colon]
+		}
+		remote = host + "/" + rest
+	} else if colon := strings.Index(remote, ":"); colon >= 0 {
+		// scp-like syntax: git@host:team/name
+		host := remote[:colon]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		remote = host + "/" + remote[colon+1:]
+	} else {
+		// Local paths are not module paths
+		return ""
+	}
+
+	if !strings.Contains(remote, "/") || strings.HasSuffix(remote, "/") {
+		return ""
+	}
+	return remote
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ProjectDir is the per-project directory holding Scotter state and overrides
+	ProjectDir = ".scotter"
+
+	// TemplatePathEnv lists organization template directories, separated like PATH
+	TemplatePathEnv = "SCOTTER_TEMPLATE_PATH"
+)
+
+// UserConfigDir returns the Scotter user configuration directory,
+// $XDG_CONFIG_HOME/scotter or ~/.config/scotter when XDG_CONFIG_HOME is unset
+func UserConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "scotter"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "scotter"), nil
+}
+
+// UserTemplateDir returns the directory holding the user's template overrides
+func UserTemplateDir() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// ProjectTemplateDir returns the project-local template override directory
+func ProjectTemplateDir(projectPath string) string {
+	return filepath.Join(projectPath, ProjectDir, "templates")
+}
+
+// OrgTemplateDirs returns the organization template directories from
+// SCOTTER_TEMPLATE_PATH or else the template_sources of the user configuration
+func OrgTemplateDirs() []string {
+	if _, ok := os.LookupEnv(TemplatePathEnv); !ok {
+		if userConfig, err := LoadUserConfig(); err == nil {
+			return userConfig.TemplateSources
+		}
+		return nil
+	}
+
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv(TemplatePathEnv)) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileDir is the directory of the organization directories holding
+// profiles, one <name>.yaml file each
+const ProfileDir = "profiles"
+
+// Profile is a named preset of project settings: a partial Config document
+// that may extend other profiles
+type Profile struct {
+	Name    string
+	Source  string
+	Extends []string
+	Values  map[string]interface{}
+}
+
+// LoadProfiles returns the profiles of the organization directories and of
+// the user configuration, the latter replacing organization profiles of the
+// same name
+func LoadProfiles() (map[string]*Profile, error) {
+	profiles := make(map[string]*Profile)
+
+	dirs := OrgTemplateDirs()
+	for i := len(dirs) - 1; i >= 0; i-- {
+		// Earlier directories take precedence, like template layers
+		files, _ := filepath.Glob(filepath.Join(dirs[i], ProfileDir, "*.yaml"))
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			var values map[string]interface{}
+			if err := yaml.Unmarshal(data, &values); err != nil {
+				return nil, fmt.Errorf("invalid profile %s: %w", file, err)
+			}
+			name := strings.TrimSuffix(filepath.Base(file), ".yaml")
+			profile, err := newProfile(name, file, values)
+			if err != nil {
+				return nil, err
+			}
+			profiles[name] = profile
+		}
+	}
+
+	userConfig, err := LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	path, _ := UserConfigPath()
+	for name, values := range userConfig.Profiles {
+		profile, err := newProfile(name, path, values)
+		if err != nil {
+			return nil, err
+		}
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
+
+// ProfileSettings returns the settings of a profile, merged over the
+// profiles it extends
+func ProfileSettings(name string) ([]Setting, error) {
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := resolveProfile(profiles, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The merged values must still decode into a Config
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&Config{}); err != nil {
+		return nil, fmt.Errorf("invalid profile '%s': %w", name, err)
+	}
+
+	return flatten(values, OriginProfile, name)
+}
+
+// newProfile checks the keys of a profile document and reads its extends
+func newProfile(name, source string, values map[string]interface{}) (*Profile, error) {
+	profile := &Profile{Name: name, Source: source, Values: make(map[string]interface{})}
+
+	fields := configFields()
+	var names []string
+	for key := range fields {
+		names = append(names, key)
+	}
+
+	for key, value := range values {
+		switch key {
+		case "extends":
+			switch v := value.(type) {
+			case string:
+				profile.Extends = []string{v}
+			case []interface{}:
+				for _, parent := range v {
+					profile.Extends = append(profile.Extends, fmt.Sprint(parent))
+				}
+			default:
+				return nil, fmt.Errorf("profile '%s' in %s: extends must be a profile name or a list of them", name, source)
+			}
+			continue
+		case "schema_version", "project_name":
+			return nil, fmt.Errorf("profile '%s' in %s: %s cannot be set by a profile", name, source, key)
+		}
+
+		if _, ok := fields[key]; !ok {
+			if suggestion := closest(key, append(names, "extends")); suggestion != "" {
+				return nil, fmt.Errorf("profile '%s' in %s: unknown key '%s', did you mean '%s'?", name, source, key, suggestion)
+			}
+			return nil, fmt.Errorf("profile '%s' in %s: unknown key '%s'", name, source, key)
+		}
+		profile.Values[key] = value
+	}
+	return profile, nil
+}
+
+// resolveProfile merges a profile over the profiles it extends, in order;
+// chain holds the profiles being resolved, to report cycles
+func resolveProfile(profiles map[string]*Profile, name string, chain []string) (map[string]interface{}, error) {
+	if containsString(chain, name) {
+		return nil, fmt.Errorf("profile '%s' extends itself: %s", name, strings.Join(append(chain, name), " -> "))
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		var names []string
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if suggestion := closest(name, names); suggestion != "" {
+			return nil, fmt.Errorf("unknown profile '%s', did you mean '%s'?", name, suggestion)
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown profile '%s', no profile is defined", name)
+		}
+		return nil, fmt.Errorf("unknown profile '%s', expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	merged := make(map[string]interface{})
+	for _, parent := range profile.Extends {
+		values, err := resolveProfile(profiles, parent, append(chain, name))
+		if err != nil {
+			return nil, err
+		}
+		mergeValues(merged, values)
+	}
+	mergeValues(merged, profile.Values)
+	return merged, nil
+}
+
+// mergeValues merges values into dst, mappings key by key and any other
+// value replacing the previous one
+func mergeValues(dst, values map[string]interface{}) {
+	for key, value := range values {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		previous, ok := dst[key].(map[string]interface{})
+		if !ok {
+			previous = make(map[string]interface{})
+		}
+		merged := make(map[string]interface{}, len(previous)+len(nested))
+		mergeValues(merged, previous)
+		mergeValues(merged, nested)
+		dst[key] = merged
+	}
+}
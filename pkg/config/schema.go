@@ -0,0 +1,129 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+)
+
+const (
+	// SchemaFile is where the JSON Schema of a project configuration is written
+	SchemaFile = ".scotter/schema.json"
+
+	// SchemaHeader points the YAML language server to the JSON Schema
+	SchemaHeader = "# yaml-language-server: $schema=" + SchemaFile
+)
+
+// Schema is a JSON Schema, limited to the keywords describing a configuration
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+}
+
+// GenerateSchema derives the JSON Schema of .scotter.yaml from Config, with
+// the values the registered plugins support as enums
+func GenerateSchema(pluginLoader plugin.PluginLoader) *Schema {
+	closed := false
+	schema := &Schema{
+		Schema:               "http://json-schema.org/draft-07/schema#",
+		Title:                "Scotter project configuration",
+		Description:          "Configuration of a project scaffolded by Scotter, stored in " + DefaultConfigFile,
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: &closed,
+		Required:             []string{"language", "project_type"},
+	}
+
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		property := typeSchema(field.Type)
+		property.Description = field.Tag.Get("doc")
+		schema.Properties[name] = property
+	}
+
+	minimum, maximum := 0, CurrentSchemaVersion
+	schema.Properties["schema_version"].Minimum = &minimum
+	schema.Properties["schema_version"].Maximum = &maximum
+
+	var languages, projectTypes, targets, assets, ciProviders []string
+	for _, langProvider := range pluginLoader.GetLanguageProviders() {
+		languages = appendUnique(languages, langProvider.Name())
+		projectTypes = appendUnique(projectTypes, langProvider.SupportedProjectTypes()...)
+		assets = appendUnique(assets, langProvider.GetSupportedReleaseAssets()...)
+		for _, target := range langProvider.GetSupportedTargets() {
+			targets = appendUnique(targets, target)
+			if parsed, err := ParseTarget(target); err == nil {
+				for _, variant := range langProvider.GetSupportedVariants(parsed.Arch) {
+					targets = appendUnique(targets, target+"/"+variant)
+				}
+			}
+		}
+	}
+	for _, ciProvider := range pluginLoader.GetCIProviders() {
+		ciProviders = appendUnique(ciProviders, ciProvider.Name())
+	}
+
+	schema.Properties["language"].Enum = sorted(languages)
+	schema.Properties["project_type"].Enum = sorted(projectTypes)
+	schema.Properties["ci_provider"].Enum = append([]string{""}, sorted(ciProviders)...)
+	schema.Properties["release_assets"].Items.Enum = sorted(assets)
+	for _, key := range []string{"targets", "ignore_targets"} {
+		schema.Properties[key].Items = &Schema{
+			AnyOf: []*Schema{
+				{Type: "string", Enum: sorted(targets)},
+				{Type: "string", Pattern: `^(?=[^*]*\*)[^/]+/[^/]+(/[^/]+)?$`, Description: "Pattern with * wildcards, such as linux/*"},
+			},
+		}
+	}
+	return schema
+}
+
+// typeSchema returns the schema of the values of a Go type
+func typeSchema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Int:
+		return &Schema{Type: "integer"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: typeSchema(t.Elem()), UniqueItems: true}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// appendUnique appends the values missing from a list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if !containsString(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// sorted returns a sorted copy of a list
+func sorted(list []string) []string {
+	result := append([]string(nil), list...)
+	sort.Strings(result)
+	return result
+}
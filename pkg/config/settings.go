@@ -0,0 +1,303 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Origins of a setting, from the lowest precedence up
+const (
+	OriginDefault = "default"
+	OriginUser    = "user"
+	OriginProfile = "profile"
+	OriginProject = "project"
+	OriginEnv     = "env"
+)
+
+// EnvPrefix starts the names of the environment variables overriding settings,
+// such as SCOTTER_CI_PROVIDER
+const EnvPrefix = "SCOTTER_"
+
+// defaultReleaseAssets are the release assets of new projects
+var defaultReleaseAssets = []string{"checksum", "sbom", "archive"}
+
+// Setting is the value of a configuration key and where it comes from
+type Setting struct {
+	Key    string      `json:"key" yaml:"key"`
+	Value  interface{} `json:"value" yaml:"value"`
+	Origin string      `json:"origin" yaml:"origin"`
+
+	// Source is the file, environment variable or flag of the origin, if any
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// Where returns the origin of the setting followed by its source, such as
+// env:SCOTTER_CI_PROVIDER
+func (s Setting) Where() string {
+	if s.Source == "" {
+		return s.Origin
+	}
+	return s.Origin + ":" + s.Source
+}
+
+// Resolve merges layers of settings, each layer taking precedence over the
+// ones before it, and returns the effective settings sorted by key
+func Resolve(layers ...[]Setting) []Setting {
+	effective := make(map[string]Setting)
+	for _, layer := range layers {
+		for _, setting := range layer {
+			effective[setting.Key] = setting
+		}
+	}
+
+	settings := make([]Setting, 0, len(effective))
+	for _, setting := range effective {
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// Lookup returns the setting of a key
+func Lookup(settings []Setting, key string) (Setting, bool) {
+	for _, setting := range settings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// LookupString returns the value of a string setting, empty when it is not set
+func LookupString(settings []Setting, key string) string {
+	setting, _ := Lookup(settings, key)
+	value, _ := setting.Value.(string)
+	return value
+}
+
+// LookupList returns the value of a list setting, nil when it is not set
+func LookupList(settings []Setting, key string) []string {
+	setting, _ := Lookup(settings, key)
+	switch value := setting.Value.(type) {
+	case []string:
+		return value
+	case []interface{}:
+		list := make([]string, len(value))
+		for i, item := range value {
+			list[i] = fmt.Sprint(item)
+		}
+		return list
+	}
+	return nil
+}
+
+// DefaultSettings returns the built-in settings of new projects
+func DefaultSettings() []Setting {
+	var targets []string
+	for _, platform := range defaultPlatforms {
+		for _, arch := range defaultArchitectures {
+			targets = append(targets, platform+"/"+arch)
+		}
+	}
+
+	return []Setting{
+		{Key: "language", Value: "go", Origin: OriginDefault},
+		{Key: "project_type", Value: "default", Origin: OriginDefault},
+		{Key: "targets", Value: targets, Origin: OriginDefault},
+		{Key: "release_assets", Value: append([]string(nil), defaultReleaseAssets...), Origin: OriginDefault},
+		{Key: "ci_provider", Value: "", Origin: OriginDefault},
+	}
+}
+
+// UserSettings returns the settings of the user configuration file
+func UserSettings() ([]Setting, error) {
+	userConfig, err := LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, nil
+	}
+
+	// Profiles are only applied on request
+	userConfig.Profiles = nil
+	return flatten(userConfig, OriginUser, path)
+}
+
+// EnvSettings returns the settings overridden by SCOTTER_* environment
+// variables; lists are separated by commas, except template_sources which is
+// read from SCOTTER_TEMPLATE_PATH
+func EnvSettings() []Setting {
+	var settings []Setting
+	for _, key := range envKeys() {
+		name, list := envVariable(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		setting := Setting{Key: key, Value: value, Origin: OriginEnv, Source: name}
+		if list {
+			var items []string
+			separator := ","
+			if name == TemplatePathEnv {
+				separator = string(filepath.ListSeparator)
+			}
+			for _, item := range strings.Split(value, separator) {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			setting.Value = items
+		}
+		settings = append(settings, setting)
+	}
+	return settings
+}
+
+// Settings returns the settings of the configuration file, leaving out the
+// environment overrides applied by Load
+func (m *Manager) Settings() ([]Setting, error) {
+	cfg := m.fileConfig()
+	settings, err := flatten(&cfg, OriginProject, DefaultConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unset keys still belong to the project, their value is empty
+	for name, field := range configFields() {
+		if _, ok := Lookup(settings, name); ok || field.Type.Kind() == reflect.Map {
+			continue
+		}
+		settings = append(settings, Setting{
+			Key:    name,
+			Value:  reflect.Zero(field.Type).Interface(),
+			Origin: OriginProject,
+			Source: DefaultConfigFile,
+		})
+	}
+	return settings, nil
+}
+
+// applyEnv overrides the settings of the loaded configuration with the
+// SCOTTER_* environment variables, remembering the values of the file
+func (m *Manager) applyEnv() {
+	fields := configFields()
+	config := reflect.ValueOf(m.Config).Elem()
+
+	m.overrides = nil
+	for _, setting := range EnvSettings() {
+		field, ok := fields[setting.Key]
+		if !ok {
+			continue
+		}
+		value := config.FieldByIndex(field.Index)
+		m.overrides = append(m.overrides, override{Setting: setting, file: value.Interface()})
+		value.Set(reflect.ValueOf(setting.Value))
+	}
+}
+
+// fileConfig returns a copy of the configuration where the settings still
+// holding an environment override get back the value of the file
+func (m *Manager) fileConfig() Config {
+	cfg := *m.Config
+	fields := configFields()
+	config := reflect.ValueOf(&cfg).Elem()
+
+	for _, o := range m.overrides {
+		value := config.FieldByIndex(fields[o.Key].Index)
+		if reflect.DeepEqual(value.Interface(), o.Value) {
+			value.Set(reflect.ValueOf(o.file))
+		}
+	}
+	return cfg
+}
+
+// envKeys returns the keys environment variables can override: the project
+// settings, apart from its name, schema version and extra_config, and the
+// user settings
+func envKeys() []string {
+	var keys []string
+	for _, t := range []reflect.Type{reflect.TypeOf(Config{}), reflect.TypeOf(UserConfig{})} {
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			switch name {
+			case "", "-", "schema_version", "project_name", "extra_config", "profiles":
+				continue
+			}
+			keys = appendUnique(keys, name)
+		}
+	}
+	return keys
+}
+
+// envVariable returns the environment variable overriding a key and whether
+// it holds a list
+func envVariable(key string) (string, bool) {
+	if key == "template_sources" {
+		return TemplatePathEnv, true
+	}
+
+	list := false
+	for _, t := range []reflect.Type{reflect.TypeOf(Config{}), reflect.TypeOf(UserConfig{})} {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name == key {
+				list = field.Type.Kind() == reflect.Slice
+			}
+		}
+	}
+	return EnvPrefix + strings.ToUpper(key), list
+}
+
+// ParseSettings returns the settings of a configuration file given its
+// content, such as a version of .scotter.yaml
+func ParseSettings(data []byte, origin, source string) ([]Setting, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return flatten(doc, origin, source)
+}
+
+// flatten returns the settings of a configuration struct, with the keys of
+// nested mappings as dotted paths such as extra_config.docker.registry
+func flatten(v interface{}, origin, source string) ([]Setting, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var settings []Setting
+	var walk func(prefix string, values map[string]interface{})
+	walk = func(prefix string, values map[string]interface{}) {
+		for key, value := range values {
+			if nested, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", nested)
+				continue
+			}
+			if items, ok := value.([]interface{}); ok {
+				list := make([]string, len(items))
+				for i, item := range items {
+					list[i] = fmt.Sprint(item)
+				}
+				value = list
+			}
+			settings = append(settings, Setting{Key: prefix + key, Value: value, Origin: origin, Source: source})
+		}
+	}
+	walk("", doc)
+	return settings, nil
+}
@@ -1,5 +1,248 @@
 package config
 
-// Note: Target validation has been moved to language providers
-// This file is kept for compatibility but will be removed in a future version
-// See pkg/plugin/interfaces.go and internal/cmd/golang/validation.go for the new implementation
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+)
+
+// defaultPlatforms and defaultArchitectures complete the targets of a new
+// platform or architecture, and the former two-list format when only one of
+// the lists was set
+var (
+	defaultPlatforms     = []string{"linux", "darwin", "windows"}
+	defaultArchitectures = []string{"amd64", "arm64"}
+)
+
+// Target is an os/arch build target, with an optional architecture variant
+// such as linux/arm/7 or linux/amd64/v3
+type Target struct {
+	OS      string
+	Arch    string
+	Variant string
+}
+
+// ParseTarget parses an os/arch or os/arch/variant target
+func ParseTarget(target string) (Target, error) {
+	parts := strings.Split(target, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Target{}, fmt.Errorf("invalid target '%s', expected os/arch or os/arch/variant", target)
+	}
+
+	t := Target{OS: parts[0], Arch: parts[1]}
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return Target{}, fmt.Errorf("invalid target '%s', empty variant", target)
+		}
+		t.Variant = parts[2]
+	}
+	return t, nil
+}
+
+// Platform returns the os/arch part of the target
+func (t Target) Platform() string {
+	return t.OS + "/" + t.Arch
+}
+
+// String returns the target in its os/arch[/variant] form
+func (t Target) String() string {
+	if t.Variant == "" {
+		return t.Platform()
+	}
+	return t.Platform() + "/" + t.Variant
+}
+
+// MatchTarget reports whether a target matches a pattern, each element of
+// the pattern being either a name or a * wildcard; a pattern without variant
+// matches every variant of its targets
+func MatchTarget(pattern, target string) bool {
+	if matched, err := path.Match(pattern, target); err == nil && matched {
+		return true
+	}
+	if t, err := ParseTarget(target); err == nil && t.Variant != "" {
+		matched, err := path.Match(pattern, t.Platform())
+		return err == nil && matched
+	}
+	return false
+}
+
+// ResolveTargets expands target patterns against the targets supported by a
+// language provider and drops the targets matching an ignore pattern
+func ResolveTargets(targets, ignore []string, langProvider plugin.LanguageSupport) []string {
+	var resolved []string
+	seen := make(map[string]bool)
+	add := func(target string) {
+		if seen[target] {
+			return
+		}
+		for _, rule := range ignore {
+			if MatchTarget(rule, target) {
+				return
+			}
+		}
+		seen[target] = true
+		resolved = append(resolved, target)
+	}
+
+	for _, pattern := range targets {
+		if !strings.Contains(pattern, "*") {
+			if langProvider.IsSupportedTarget(pattern) {
+				add(pattern)
+			}
+			continue
+		}
+		for _, target := range langProvider.GetSupportedTargets() {
+			if MatchTarget(pattern, target) {
+				add(target)
+			}
+		}
+	}
+	return resolved
+}
+
+// EffectiveTargets returns the targets the project is built for
+func (m *Manager) EffectiveTargets(langProvider plugin.LanguageSupport) []string {
+	return ResolveTargets(m.Config.Targets, m.Config.IgnoreTargets, langProvider)
+}
+
+// AddTarget adds an os/arch target, or a pattern such as linux/*, if not already present
+func (m *Manager) AddTarget(target string, langProvider plugin.LanguageSupport) error {
+	if err := validateTargetPattern(target, langProvider); err != nil {
+		return err
+	}
+
+	for _, t := range m.Config.Targets {
+		if t == target {
+			return &scerrors.AlreadyExistsError{Kind: "target", Name: target, Path: m.ConfigPath}
+		}
+	}
+
+	m.Config.Targets = append(m.Config.Targets, target)
+	return nil
+}
+
+// RemoveTarget removes a target
+func (m *Manager) RemoveTarget(target string) error {
+	for i, t := range m.Config.Targets {
+		if t == target {
+			m.Config.Targets = append(m.Config.Targets[:i], m.Config.Targets[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("target '%s' not found", target)
+}
+
+// AddIgnoreTarget adds a rule excluding the targets matching a pattern
+func (m *Manager) AddIgnoreTarget(pattern string, langProvider plugin.LanguageSupport) error {
+	if err := validateTargetPattern(pattern, langProvider); err != nil {
+		return err
+	}
+
+	for _, rule := range m.Config.IgnoreTargets {
+		if rule == pattern {
+			return &scerrors.AlreadyExistsError{Kind: "ignore rule", Name: pattern, Path: m.ConfigPath}
+		}
+	}
+
+	m.Config.IgnoreTargets = append(m.Config.IgnoreTargets, pattern)
+	return nil
+}
+
+// RemoveIgnoreTarget removes an ignore rule
+func (m *Manager) RemoveIgnoreTarget(pattern string) error {
+	for i, rule := range m.Config.IgnoreTargets {
+		if rule == pattern {
+			m.Config.IgnoreTargets = append(m.Config.IgnoreTargets[:i], m.Config.IgnoreTargets[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("ignore rule '%s' not found", pattern)
+}
+
+// validateTargetPattern checks that a target, variant included, is supported
+// by the language provider, or that a pattern matches at least one supported target
+func validateTargetPattern(pattern string, langProvider plugin.LanguageSupport) error {
+	target, err := ParseTarget(pattern)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(pattern, "*") {
+		if !langProvider.IsSupportedTarget(target.Platform()) {
+			return &scerrors.UnsupportedTargetError{Kind: "target", Value: target.Platform(), Provider: langProvider.Name()}
+		}
+		if target.Variant != "" && !langProvider.IsSupportedTarget(pattern) {
+			variants := langProvider.GetSupportedVariants(target.Arch)
+			if len(variants) == 0 {
+				return fmt.Errorf("architecture '%s' has no variants", target.Arch)
+			}
+			return &scerrors.UnsupportedTargetError{Kind: "variant", Value: pattern, Provider: langProvider.Name(), Supported: variants}
+		}
+		return nil
+	}
+
+	for _, supported := range langProvider.GetSupportedTargets() {
+		if MatchTarget(pattern, supported) {
+			return nil
+		}
+	}
+	return fmt.Errorf("pattern '%s' matches no target supported by %s", pattern, langProvider.Name())
+}
+
+// targetElements returns the distinct operating systems or architectures of
+// the literal targets
+func (m *Manager) targetElements(arch bool) []string {
+	var elements []string
+	for _, pattern := range m.Config.Targets {
+		target, err := ParseTarget(pattern)
+		if err != nil || strings.Contains(pattern, "*") {
+			continue
+		}
+		element := target.OS
+		if arch {
+			element = target.Arch
+		}
+		if !containsString(elements, element) {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// removeTargets removes the targets whose operating system or architecture is element
+func (m *Manager) removeTargets(element string, arch bool) bool {
+	kept := m.Config.Targets[:0]
+	removed := false
+	for _, pattern := range m.Config.Targets {
+		target, _ := ParseTarget(pattern)
+		if (!arch && target.OS == element) || (arch && target.Arch == element) {
+			removed = true
+			continue
+		}
+		kept = append(kept, pattern)
+	}
+	m.Config.Targets = kept
+	return removed
+}
+
+// addTargetOnce adds a target unless already present
+func (m *Manager) addTargetOnce(target string) {
+	if !containsString(m.Config.Targets, target) {
+		m.Config.Targets = append(m.Config.Targets, target)
+	}
+}
+
+// containsString checks if a slice contains a string
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
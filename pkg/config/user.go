@@ -0,0 +1,85 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserConfigFile is the name of the user-level configuration file
+const UserConfigFile = "config.yaml"
+
+// UserConfig holds the user-level settings shared by every project, used as
+// defaults when initializing one
+type UserConfig struct {
+	// ModulePrefix is prepended to the project name to build default module paths
+	ModulePrefix string `yaml:"module_prefix,omitempty"`
+
+	// Author and License are passed to the templates of new projects
+	Author  string `yaml:"author,omitempty"`
+	License string `yaml:"license,omitempty"`
+
+	// Targets, ReleaseAssets and CIProvider replace the built-in defaults of new projects
+	Targets       []string `yaml:"targets,omitempty"`
+	ReleaseAssets []string `yaml:"release_assets,omitempty"`
+	CIProvider    string   `yaml:"ci_provider,omitempty"`
+
+	// TemplateSources lists organization template directories, used when
+	// SCOTTER_TEMPLATE_PATH is not set
+	TemplateSources []string `yaml:"template_sources,omitempty"`
+
+	// Profiles are named presets of project settings, see Profile
+	Profiles map[string]map[string]interface{} `yaml:"profiles,omitempty"`
+}
+
+// UserConfigPath returns the path of the user configuration file
+func UserConfigPath() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, UserConfigFile), nil
+}
+
+// LoadUserConfig loads the user configuration, a missing file yielding empty settings
+func LoadUserConfig() (*UserConfig, error) {
+	userConfig := &UserConfig{}
+
+	path, err := UserConfigPath()
+	if err != nil {
+		return userConfig, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return userConfig, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(userConfig); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid user configuration %s: %w", path, err)
+	}
+
+	// Template sources are relative to the user configuration directory
+	for i, source := range userConfig.TemplateSources {
+		if rest, ok := strings.CutPrefix(source, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				source = filepath.Join(home, rest)
+			}
+		}
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(filepath.Dir(path), source)
+		}
+		userConfig.TemplateSources[i] = source
+	}
+	return userConfig, nil
+}
@@ -0,0 +1,343 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic is a problem found in a configuration file, located at the line
+// and column of the offending node when it is known
+type Diagnostic struct {
+	Line    int    `json:"line" yaml:"line"`
+	Column  int    `json:"column" yaml:"column"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// ValidationError lists every problem found in a configuration file
+type ValidationError struct {
+	File        string
+	Diagnostics []Diagnostic
+}
+
+// Is reports whether target is scerrors.ErrConfigInvalid
+func (e *ValidationError) Is(target error) bool {
+	return target == scerrors.ErrConfigInvalid
+}
+
+// Error returns one file:line:column: message line per problem
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		if d.Line > 0 {
+			lines[i] = fmt.Sprintf("%s:%d:%d: %s", e.File, d.Line, d.Column, d.Message)
+		} else {
+			lines[i] = fmt.Sprintf("%s: %s", e.File, d.Message)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diagnostics collects the problems of a configuration, locating them in the
+// file as it was loaded
+type diagnostics struct {
+	file string
+	root *yaml.Node
+	list []Diagnostic
+
+	// env maps the keys overridden by the environment to their variable
+	env map[string]string
+}
+
+// add records a problem with the node at path, or with its key when key is set
+func (d *diagnostics) add(path []interface{}, key bool, format string, args ...interface{}) {
+	diag := Diagnostic{Message: fmt.Sprintf(format, args...)}
+	if len(path) > 0 {
+		if name, ok := d.env[fmt.Sprint(path[0])]; ok {
+			// The value does not come from the file
+			diag.Message += fmt.Sprintf(" (set by %s)", name)
+			d.list = append(d.list, diag)
+			return
+		}
+	}
+	if node := locate(d.root, path, key); node != nil {
+		diag.Line, diag.Column = node.Line, node.Column
+	}
+	d.list = append(d.list, diag)
+}
+
+// err returns the problems as a ValidationError, nil when there are none
+func (d *diagnostics) err() error {
+	if len(d.list) == 0 {
+		return nil
+	}
+	sort.SliceStable(d.list, func(i, j int) bool {
+		return d.list[i].Line < d.list[j].Line ||
+			(d.list[i].Line == d.list[j].Line && d.list[i].Column < d.list[j].Column)
+	})
+	return &ValidationError{File: d.file, Diagnostics: d.list}
+}
+
+// diagnostics returns a collector for the problems of the loaded configuration
+func (m *Manager) diagnostics() *diagnostics {
+	file := m.ConfigPath
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, m.ConfigPath); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	env := make(map[string]string, len(m.overrides))
+	for _, o := range m.overrides {
+		env[o.Key] = o.Source
+	}
+	return &diagnostics{file: file, root: m.root, env: env}
+}
+
+// Check loads the configuration and validates it, reporting the problems
+// found by both steps together
+func (m *Manager) Check(pluginLoader plugin.PluginLoader) error {
+	err := m.Load()
+	var invalid *ValidationError
+	if err != nil && !(errors.As(err, &invalid) && len(m.problems) > 0) {
+		return err
+	}
+
+	return m.Validate(pluginLoader)
+}
+
+// Validate checks the loaded configuration against the registered providers:
+// the language, project type, CI provider, targets and release assets must
+// all be supported
+func (m *Manager) Validate(pluginLoader plugin.PluginLoader) error {
+	d := m.diagnostics()
+	d.list = append(d.list, m.problems...)
+	cfg := m.Config
+
+	langProvider, err := pluginLoader.GetLanguageProvider(cfg.Language)
+	if err != nil {
+		var names []string
+		for _, provider := range pluginLoader.GetLanguageProviders() {
+			names = append(names, provider.Name())
+		}
+		d.add([]interface{}{"language"}, false, "unsupported language '%s', expected one of %s", cfg.Language, joinSorted(names))
+	} else {
+		if !containsString(langProvider.SupportedProjectTypes(), cfg.ProjectType) {
+			d.add([]interface{}{"project_type"}, false, "unsupported project type '%s' for %s, expected one of %s",
+				cfg.ProjectType, langProvider.Name(), joinSorted(langProvider.SupportedProjectTypes()))
+		}
+		for i, target := range cfg.Targets {
+			if err := validateTargetPattern(target, langProvider); err != nil {
+				d.add([]interface{}{"targets", i}, false, "%s", err)
+			}
+		}
+		for i, pattern := range cfg.IgnoreTargets {
+			if err := validateTargetPattern(pattern, langProvider); err != nil {
+				d.add([]interface{}{"ignore_targets", i}, false, "%s", err)
+			}
+		}
+		for i, asset := range cfg.ReleaseAssets {
+			if !langProvider.IsSupportedReleaseAsset(asset) {
+				d.add([]interface{}{"release_assets", i}, false, "unsupported release asset '%s' for %s, expected one of %s",
+					asset, langProvider.Name(), joinSorted(langProvider.GetSupportedReleaseAssets()))
+			}
+		}
+	}
+
+	if cfg.CIProvider != "" {
+		ciProvider, err := pluginLoader.GetCIProvider(cfg.CIProvider)
+		if err != nil {
+			var names []string
+			for _, provider := range pluginLoader.GetCIProviders() {
+				names = append(names, provider.Name())
+			}
+			d.add([]interface{}{"ci_provider"}, false, "unknown CI provider '%s', expected one of %s", cfg.CIProvider, joinSorted(names))
+		} else if langProvider != nil && !containsString(ciProvider.SupportedLanguages(), cfg.Language) {
+			d.add([]interface{}{"ci_provider"}, false, "CI provider '%s' does not support language '%s'", cfg.CIProvider, cfg.Language)
+		}
+	}
+
+	return d.err()
+}
+
+// checkStructure reports the keys of a configuration document that Config
+// does not define and the values that do not have the type of their field
+func checkStructure(root *yaml.Node, d *diagnostics) {
+	doc := root
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return
+		}
+		doc = doc.Content[0]
+	}
+	if doc.Kind != yaml.MappingNode {
+		d.add(nil, false, "the configuration must be a mapping of keys to values")
+		return
+	}
+
+	fields := configFields()
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i].Value, resolveAlias(doc.Content[i+1])
+		field, ok := fields[key]
+		if !ok {
+			if suggestion := closest(key, names); suggestion != "" {
+				d.add([]interface{}{key}, true, "unknown key '%s', did you mean '%s'?", key, suggestion)
+			} else {
+				d.add([]interface{}{key}, true, "unknown key '%s'", key)
+			}
+			continue
+		}
+		checkValue(key, field.Type, value, d)
+	}
+}
+
+// checkValue reports a value that cannot be decoded into a field of type t
+func checkValue(key string, t reflect.Type, value *yaml.Node, d *diagnostics) {
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+		return
+	}
+
+	path := []interface{}{key}
+	switch t.Kind() {
+	case reflect.String:
+		if value.Kind != yaml.ScalarNode {
+			d.add(path, false, "%s must be a string", key)
+		}
+	case reflect.Int:
+		if value.Kind != yaml.ScalarNode || value.Tag != "!!int" {
+			d.add(path, false, "%s must be an integer", key)
+		}
+	case reflect.Slice:
+		if value.Kind != yaml.SequenceNode {
+			d.add(path, false, "%s must be a list", key)
+			return
+		}
+		for i, item := range value.Content {
+			if resolveAlias(item).Kind != yaml.ScalarNode {
+				d.add([]interface{}{key, i}, false, "%s items must be strings", key)
+			}
+		}
+	case reflect.Map:
+		if value.Kind != yaml.MappingNode {
+			d.add(path, false, "%s must be a mapping", key)
+		}
+	}
+}
+
+// configFields maps the YAML keys of Config to their fields
+func configFields() map[string]reflect.StructField {
+	t := reflect.TypeOf(Config{})
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// locate returns the node at a path of mapping keys and sequence indexes, or
+// the key node holding it when key is set; nil when the path is not there
+func locate(root *yaml.Node, path []interface{}, key bool) *yaml.Node {
+	if root == nil {
+		return nil
+	}
+	node := root
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+
+	var keyNode *yaml.Node
+	for _, step := range path {
+		node = resolveAlias(node)
+		switch s := step.(type) {
+		case string:
+			if node.Kind != yaml.MappingNode {
+				return nil
+			}
+			found := false
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == s {
+					keyNode, node = node.Content[i], node.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil
+			}
+		case int:
+			if node.Kind != yaml.SequenceNode || s >= len(node.Content) {
+				return nil
+			}
+			keyNode, node = nil, node.Content[s]
+		}
+	}
+
+	if key && keyNode != nil {
+		return keyNode
+	}
+	return node
+}
+
+// resolveAlias returns the node an alias refers to
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias
+	}
+	return node
+}
+
+// closest returns the candidate within two edits of name, if any
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// joinSorted returns the names sorted and separated by commas
+func joinSorted(names []string) string {
+	return strings.Join(sorted(names), ", ")
+}
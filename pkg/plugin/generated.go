@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+)
+
+// GeneratedFile is a file rendered by a provider, not yet written to disk
+type GeneratedFile struct {
+	// Path is the file path, relative to the project directory and slash separated
+	Path string
+
+	// Template is the template the file was rendered from, empty for files
+	// written without a template
+	Template string
+
+	// Version is the version of the template set the template belongs to
+	Version string
+
+	// Content is the rendered content of the file
+	Content []byte
+}
+
+// RenderManifest renders the files of a template set whose condition holds
+func RenderManifest(templateManager TemplateManager, manifest *Manifest, data interface{}) ([]GeneratedFile, error) {
+	var files []GeneratedFile
+	for _, file := range manifest.Files {
+		ok, err := EvaluateCondition(file.When, data)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		target, err := RenderExpression(file.Target, data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target '%s': %w", file.Target, err)
+		}
+		content, err := templateManager.RenderToString(manifest.TemplatePath(file), data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", target, err)
+		}
+
+		files = append(files, GeneratedFile{
+			Path:     path.Clean(target),
+			Template: manifest.TemplatePath(file),
+			Version:  manifest.Version,
+			Content:  []byte(content),
+		})
+	}
+	return files, nil
+}
+
+// WriteGeneratedFiles writes rendered files inside a project directory of fsys
+func WriteGeneratedFiles(fsys vfs.FS, projectPath string, files []GeneratedFile) error {
+	for _, file := range files {
+		target := filepath.Join(projectPath, filepath.FromSlash(file.Path))
+		if err := fsys.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+		}
+		if err := fsys.WriteFile(target, file.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
@@ -1,7 +1,54 @@
 // Package plugin defines the core interfaces for Scotter's plugin system
 package plugin
 
-// LanguageProvider is the main interface for language plugins
+import "github.com/caezarr-oss/scotter/pkg/vfs"
+
+// LanguageSupport describes what a language provider of the second version
+// supports; package provider derives it for providers of the first version
+type LanguageSupport interface {
+	// Name returns the plugin name
+	Name() string
+	
+	// SupportedProjectTypes returns project types supported by this language
+	SupportedProjectTypes() []string
+
+	// SetFilesystem sets the filesystem the provider reads and writes project files through
+	SetFilesystem(fsys vfs.FS)
+	
+	// IsSupportedPlatform checks if a platform is supported by this language
+	IsSupportedPlatform(platform string) bool
+	
+	// IsSupportedArchitecture checks if an architecture is supported by this language
+	IsSupportedArchitecture(arch string) bool
+	
+	// IsSupportedTarget checks if an os/arch or os/arch/variant target is supported by this language
+	IsSupportedTarget(target string) bool
+	
+	// IsSupportedReleaseAsset checks if a release asset type is supported by this language
+	IsSupportedReleaseAsset(assetType string) bool
+	
+	// GetSupportedPlatforms returns all supported platforms for this language
+	GetSupportedPlatforms() []string
+	
+	// GetSupportedArchitectures returns all supported architectures for this language
+	GetSupportedArchitectures() []string
+	
+	// GetSupportedTargets returns all supported os/arch targets for this language
+	GetSupportedTargets() []string
+
+	// GetSupportedVariants returns the variants of an architecture, such as
+	// the ARM versions, or nil when it has none
+	GetSupportedVariants(arch string) []string
+	
+	// GetSupportedReleaseAssets returns all supported release asset types for this language
+	GetSupportedReleaseAssets() []string
+}
+
+// LanguageProvider is the first version of the language plugin interface.
+// It resolves the project directory from the project name, relative to the
+// working directory, receives its settings as an untyped map and writes its
+// files itself. Register such providers through provider.FromV1; see package
+// provider for the second version.
 type LanguageProvider interface {
 	// Name returns the plugin name
 	Name() string
@@ -40,7 +87,22 @@ type LanguageProvider interface {
 	GetSupportedReleaseAssets() []string
 }
 
-// CIProvider is the interface for continuous integration plugins
+// CISupport describes what a CI provider of the second version supports;
+// package provider derives it for providers of the first version
+type CISupport interface {
+	// Name returns the CI provider name
+	Name() string
+	
+	// SupportedLanguages returns languages supported by this provider
+	SupportedLanguages() []string
+
+	// SetFilesystem sets the filesystem the provider reads and writes project files through
+	SetFilesystem(fsys vfs.FS)
+}
+
+// CIProvider is the first version of the continuous integration plugin
+// interface. Register such providers through provider.CIFromV1; see package
+// provider for the second version.
 type CIProvider interface {
 	// Name returns the CI provider name
 	Name() string
@@ -52,23 +114,25 @@ type CIProvider interface {
 	GenerateWorkflows(projectPath, language, projectType string, config map[string]interface{}) error
 }
 
-// PluginLoader handles the registration and management of plugins
+// PluginLoader handles the registration and management of plugins. Providers
+// of the first version of the API are registered once adapted by package
+// provider.
 type PluginLoader interface {
 	// RegisterLanguageProvider registers a new language provider
-	RegisterLanguageProvider(provider LanguageProvider)
+	RegisterLanguageProvider(provider LanguageSupport)
 	
 	// RegisterCIProvider registers a new CI provider
-	RegisterCIProvider(provider CIProvider)
+	RegisterCIProvider(provider CISupport)
 	
 	// GetLanguageProvider retrieves a language provider by name
-	GetLanguageProvider(name string) (LanguageProvider, error)
+	GetLanguageProvider(name string) (LanguageSupport, error)
 	
 	// GetCIProvider retrieves a CI provider by name
-	GetCIProvider(name string) (CIProvider, error)
+	GetCIProvider(name string) (CISupport, error)
 	
 	// GetLanguageProviders returns all registered language providers
-	GetLanguageProviders() []LanguageProvider
+	GetLanguageProviders() []LanguageSupport
 	
 	// GetCIProviders returns all registered CI providers
-	GetCIProviders() []CIProvider
+	GetCIProviders() []CISupport
 }
@@ -1,66 +1,67 @@
 package plugin
 
 import (
-	"fmt"
 	"sync"
+
+	"github.com/caezarr-oss/scotter/pkg/scerrors"
 )
 
 // DefaultPluginLoader is the default implementation of PluginLoader
 type DefaultPluginLoader struct {
-	languageProviders map[string]LanguageProvider
-	ciProviders       map[string]CIProvider
+	languageProviders map[string]LanguageSupport
+	ciProviders       map[string]CISupport
 	mu                sync.RWMutex
 }
 
 // NewPluginLoader creates a new plugin loader
 func NewPluginLoader() *DefaultPluginLoader {
 	return &DefaultPluginLoader{
-		languageProviders: make(map[string]LanguageProvider),
-		ciProviders:       make(map[string]CIProvider),
+		languageProviders: make(map[string]LanguageSupport),
+		ciProviders:       make(map[string]CISupport),
 	}
 }
 
 // RegisterLanguageProvider registers a new language provider
-func (l *DefaultPluginLoader) RegisterLanguageProvider(provider LanguageProvider) {
+func (l *DefaultPluginLoader) RegisterLanguageProvider(provider LanguageSupport) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.languageProviders[provider.Name()] = provider
 }
 
 // RegisterCIProvider registers a new CI provider
-func (l *DefaultPluginLoader) RegisterCIProvider(provider CIProvider) {
+func (l *DefaultPluginLoader) RegisterCIProvider(provider CISupport) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.ciProviders[provider.Name()] = provider
 }
 
 // GetLanguageProvider retrieves a language provider by name
-func (l *DefaultPluginLoader) GetLanguageProvider(name string) (LanguageProvider, error) {
+func (l *DefaultPluginLoader) GetLanguageProvider(name string) (LanguageSupport, error) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	provider, ok := l.languageProviders[name]
 	if !ok {
-		return nil, fmt.Errorf("language provider '%s' not found", name)
+		return nil, &scerrors.ProviderNotFoundError{Kind: "language", Provider: name}
 	}
 	return provider, nil
 }
 
 // GetCIProvider retrieves a CI provider by name
-func (l *DefaultPluginLoader) GetCIProvider(name string) (CIProvider, error) {
+func (l *DefaultPluginLoader) GetCIProvider(name string) (CISupport, error) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	provider, ok := l.ciProviders[name]
 	if !ok {
-		return nil, fmt.Errorf("CI provider '%s' not found", name)
+		return nil, &scerrors.ProviderNotFoundError{Kind: "CI", Provider: name}
 	}
 	return provider, nil
 }
 
 // GetLanguageProviders returns all registered language providers
-func (l *DefaultPluginLoader) GetLanguageProviders() []LanguageProvider {
+func (l *DefaultPluginLoader) GetLanguageProviders() []LanguageSupport {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	providers := make([]LanguageProvider, 0, len(l.languageProviders))
+	providers := make([]LanguageSupport, 0, len(l.languageProviders))
 	for _, provider := range l.languageProviders {
 		providers = append(providers, provider)
 	}
@@ -68,10 +69,10 @@ func (l *DefaultPluginLoader) GetLanguageProviders() []LanguageProvider {
 }
 
 // GetCIProviders returns all registered CI providers
-func (l *DefaultPluginLoader) GetCIProviders() []CIProvider {
+func (l *DefaultPluginLoader) GetCIProviders() []CISupport {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	providers := make([]CIProvider, 0, len(l.ciProviders))
+	providers := make([]CISupport, 0, len(l.ciProviders))
 	for _, provider := range l.ciProviders {
 		providers = append(providers, provider)
 	}
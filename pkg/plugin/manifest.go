@@ -0,0 +1,190 @@
+package plugin
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest describing a template set
+const ManifestFile = "template.yaml"
+
+// Manifest describes the files, variables and post-render steps of a template set
+type Manifest struct {
+	Name        string             `yaml:"name"`
+	Description string             `yaml:"description,omitempty"`
+	Language    string             `yaml:"language,omitempty"`
+	Version     string             `yaml:"version,omitempty"`
+	Variables   []ManifestVariable `yaml:"variables,omitempty"`
+	Directories []string           `yaml:"directories,omitempty"`
+
+	// Dependencies lists the modules or packages the rendered project
+	// requires, either bare or pinned as name@version
+	Dependencies []string `yaml:"dependencies,omitempty"`
+
+	Files      []ManifestFileSpec `yaml:"files"`
+	PostRender []PostRenderStep   `yaml:"post_render,omitempty"`
+
+	// Dir is the directory of the manifest inside the template filesystem
+	Dir string `yaml:"-"`
+}
+
+// ManifestVariable declares a variable available to the templates of a set
+type ManifestVariable struct {
+	Name        string      `yaml:"name"`
+	Type        string      `yaml:"type,omitempty"`
+	Default     interface{} `yaml:"default,omitempty"`
+	Required    bool        `yaml:"required,omitempty"`
+	Description string      `yaml:"description,omitempty"`
+}
+
+// ManifestFileSpec describes a single file to render
+type ManifestFileSpec struct {
+	// Template is the template path, relative to the manifest directory
+	Template string `yaml:"template"`
+
+	// Target is the output path inside the project, itself a template
+	Target string `yaml:"target"`
+
+	// When is an optional template expression; the file is skipped unless it renders to "true"
+	When string `yaml:"when,omitempty"`
+}
+
+// PostRenderStep is a command run in the project directory once all files are rendered
+type PostRenderStep struct {
+	Run      string `yaml:"run"`
+	When     string `yaml:"when,omitempty"`
+	Optional bool   `yaml:"optional,omitempty"`
+}
+
+// LoadManifest reads and validates the manifest stored in dir
+func LoadManifest(fsys fs.FS, dir string) (*Manifest, error) {
+	data, err := fs.ReadFile(fsys, path.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path.Join(dir, ManifestFile), err)
+	}
+	manifest.Dir = dir
+
+	if manifest.Name == "" {
+		manifest.Name = path.Base(dir)
+	}
+	for _, v := range manifest.Variables {
+		switch v.Type {
+		case "", "string", "bool", "int", "list":
+		default:
+			return nil, fmt.Errorf("variable '%s' in %s has unsupported type '%s'", v.Name, manifest.Name, v.Type)
+		}
+	}
+	for _, f := range manifest.Files {
+		if f.Template == "" || f.Target == "" {
+			return nil, fmt.Errorf("every file in %s needs a template and a target", manifest.Name)
+		}
+	}
+
+	return manifest, nil
+}
+
+// TemplatePath resolves a file template path relative to the manifest directory
+func (m *Manifest) TemplatePath(file ManifestFileSpec) string {
+	return path.Join(m.Dir, file.Template)
+}
+
+// ResolveVariables returns the value of every declared variable, taking
+// values from the provided settings and falling back to the defaults
+func (m *Manifest) ResolveVariables(settings map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(m.Variables))
+	for _, v := range m.Variables {
+		value, ok := settings[v.Name]
+		if !ok || value == nil {
+			if v.Required && v.Default == nil {
+				return nil, fmt.Errorf("variable '%s' is required by template '%s'", v.Name, m.Name)
+			}
+			value = v.Default
+		}
+
+		converted, err := convertVariable(v, value)
+		if err != nil {
+			return nil, err
+		}
+		values[v.Name] = converted
+	}
+	return values, nil
+}
+
+// convertVariable coerces a value to the declared type of a variable
+func convertVariable(v ManifestVariable, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v.Type {
+	case "bool":
+		switch val := value.(type) {
+		case bool:
+			return val, nil
+		case string:
+			if b, err := strconv.ParseBool(val); err == nil {
+				return b, nil
+			}
+		}
+	case "int":
+		switch val := value.(type) {
+		case int:
+			return val, nil
+		case string:
+			if i, err := strconv.Atoi(val); err == nil {
+				return i, nil
+			}
+		}
+	case "list":
+		switch val := value.(type) {
+		case []interface{}:
+			return val, nil
+		case []string:
+			return val, nil
+		case string:
+			return strings.Split(val, ","), nil
+		}
+	default:
+		return fmt.Sprint(value), nil
+	}
+
+	return nil, fmt.Errorf("variable '%s' expects a %s, got %v", v.Name, v.Type, value)
+}
+
+// RenderExpression renders an inline template such as a target path or a condition
+func RenderExpression(expr string, data interface{}) (string, error) {
+	tmpl, err := template.New("expr").Option("missingkey=zero").Parse(expr)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// EvaluateCondition reports whether a "when" expression holds; an empty expression always holds
+func EvaluateCondition(expr string, data interface{}) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+
+	result, err := RenderExpression(expr, data)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition %q: %w", expr, err)
+	}
+	return strings.TrimSpace(result) == "true", nil
+}
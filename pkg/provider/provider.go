@@ -0,0 +1,131 @@
+// Package provider defines the second version of the provider API. Providers
+// receive a context, cancelled when the user interrupts Scotter, and the
+// project they act on: its root directory, its typed configuration and the
+// output their messages go to.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+)
+
+// Project is the project a provider acts on
+type Project struct {
+	// Root is the absolute path of the project directory
+	Root string
+
+	// Config is the configuration of the project
+	Config *config.Config
+
+	// Out receives the messages of the provider meant for the user
+	Out Output
+}
+
+// Output receives the messages of providers
+type Output interface {
+	// Infof reports progress or a hint
+	Infof(format string, args ...interface{})
+
+	// Warnf reports a problem that does not stop the operation
+	Warnf(format string, args ...interface{})
+}
+
+// LanguageProvider is the second version of the language plugin interface
+type LanguageProvider interface {
+	plugin.LanguageSupport
+
+	// Initialize writes the files of a new project
+	Initialize(ctx context.Context, project *Project) error
+
+	// RenderProject renders the files of the project type without writing them
+	RenderProject(ctx context.Context, project *Project) ([]plugin.GeneratedFile, error)
+
+	// GenerateReleaseScript writes the release script of the project
+	GenerateReleaseScript(ctx context.Context, project *Project) error
+
+	// RenderReleaseScript renders the release script files without writing them
+	RenderReleaseScript(ctx context.Context, project *Project) ([]plugin.GeneratedFile, error)
+
+	// SetTargets updates the project build matrix to the given os/arch targets
+	SetTargets(ctx context.Context, project *Project, targets []string) error
+
+	// AddReleaseAsset adds support for a release asset type
+	AddReleaseAsset(ctx context.Context, project *Project, assetType string) error
+
+	// RemoveReleaseAsset removes support for a release asset type
+	RemoveReleaseAsset(ctx context.Context, project *Project, assetType string) error
+}
+
+// CIProvider is the second version of the continuous integration plugin interface
+type CIProvider interface {
+	plugin.CISupport
+
+	// GenerateWorkflows writes the CI workflows of the project
+	GenerateWorkflows(ctx context.Context, project *Project) error
+
+	// RenderWorkflows renders the CI workflow files without writing them
+	RenderWorkflows(ctx context.Context, project *Project) ([]plugin.GeneratedFile, error)
+}
+
+// Language returns the language provider registered under a name; providers
+// of the first version are registered once adapted by FromV1
+func Language(loader plugin.PluginLoader, name string) (LanguageProvider, error) {
+	registered, err := loader.GetLanguageProvider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if p, ok := registered.(LanguageProvider); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("language provider '%s' implements no known version of the provider API", name)
+}
+
+// CI returns the CI provider registered under a name; providers of the first
+// version are registered once adapted by CIFromV1
+func CI(loader plugin.PluginLoader, name string) (CIProvider, error) {
+	registered, err := loader.GetCIProvider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if p, ok := registered.(CIProvider); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("CI provider '%s' implements no known version of the provider API", name)
+}
+
+// Settings returns the settings of a project as the untyped map of the first
+// version of the API, exposing the module path and CI provider to templates
+func Settings(cfg *config.Config) map[string]interface{} {
+	settings := map[string]interface{}{
+		"ci_provider": cfg.CIProvider,
+		"module_path": cfg.ModulePath,
+	}
+	for k, v := range cfg.ExtraConfig {
+		settings[k] = v
+	}
+	return settings
+}
+
+// writerOutput writes messages to a writer, warnings with a prefix
+type writerOutput struct {
+	w io.Writer
+}
+
+// NewOutput returns an output writing messages to w, one per line
+func NewOutput(w io.Writer) Output {
+	return writerOutput{w: w}
+}
+
+func (o writerOutput) Infof(format string, args ...interface{}) {
+	fmt.Fprintf(o.w, format+"\n", args...)
+}
+
+func (o writerOutput) Warnf(format string, args ...interface{}) {
+	fmt.Fprintf(o.w, "Warning: "+format+"\n", args...)
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+)
+
+// v1Language adapts a language provider of the first version
+type v1Language struct {
+	plugin.LanguageProvider
+	fsys vfs.FS
+}
+
+// FromV1 adapts a language provider of the first version, so that it can be
+// registered with a plugin loader. Its operations run once the context is
+// checked, since they cannot be interrupted, and what it prints to the
+// standard output goes to the output of the project.
+//
+// Such a provider writes its files itself, straight to the disk: they are
+// neither rendered ahead nor tracked in .scotter.lock. Its operations writing
+// files therefore fail unless its filesystem is vfs.OS, as with --dry-run or
+// while a transaction is open. Its targets are the combinations of its
+// platforms and architectures, without variants.
+func FromV1(p plugin.LanguageProvider) LanguageProvider {
+	return &v1Language{LanguageProvider: p, fsys: vfs.OS}
+}
+
+// SetFilesystem sets the filesystem the provider must write to, which has to
+// be vfs.OS for its operations to run
+func (a *v1Language) SetFilesystem(fsys vfs.FS) {
+	a.fsys = fsys
+}
+
+func (a *v1Language) IsSupportedTarget(target string) bool {
+	parsed, err := config.ParseTarget(target)
+	if err != nil || parsed.Variant != "" {
+		return false
+	}
+	return a.IsSupportedPlatform(parsed.OS) && a.IsSupportedArchitecture(parsed.Arch)
+}
+
+func (a *v1Language) GetSupportedTargets() []string {
+	var targets []string
+	for _, platform := range a.GetSupportedPlatforms() {
+		for _, arch := range a.GetSupportedArchitectures() {
+			targets = append(targets, platform+"/"+arch)
+		}
+	}
+	return targets
+}
+
+func (a *v1Language) GetSupportedVariants(arch string) []string {
+	return nil
+}
+
+// Initialize passes the project root as the project name, which the first
+// version resolves the project directory from
+func (a *v1Language) Initialize(ctx context.Context, project *Project) error {
+	if err := checkV1(ctx, a.Name(), a.fsys); err != nil {
+		return err
+	}
+	return capture(project, func() error {
+		return a.LanguageProvider.Initialize(project.Root, project.Config.ProjectType, Settings(project.Config))
+	})
+}
+
+// RenderProject returns no file, the first version cannot render without writing
+func (a *v1Language) RenderProject(ctx context.Context, project *Project) ([]plugin.GeneratedFile, error) {
+	return nil, ctx.Err()
+}
+
+func (a *v1Language) GenerateReleaseScript(ctx context.Context, project *Project) error {
+	if err := checkV1(ctx, a.Name(), a.fsys); err != nil {
+		return err
+	}
+	return capture(project, func() error {
+		return a.LanguageProvider.GenerateReleaseScript(project.Root, releaseSettings(project))
+	})
+}
+
+// RenderReleaseScript returns no file, the first version cannot render without writing
+func (a *v1Language) RenderReleaseScript(ctx context.Context, project *Project) ([]plugin.GeneratedFile, error) {
+	return nil, ctx.Err()
+}
+
+// SetTargets adds the platforms of the targets, the only change the first
+// version supports; the platforms it refuses, such as the ones it already
+// has, are reported as warnings
+func (a *v1Language) SetTargets(ctx context.Context, project *Project, targets []string) error {
+	if err := checkV1(ctx, a.Name(), a.fsys); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	return capture(project, func() error {
+		for _, target := range targets {
+			parsed, err := config.ParseTarget(target)
+			if err != nil || seen[parsed.OS] {
+				continue
+			}
+			seen[parsed.OS] = true
+			if err := a.LanguageProvider.AddPlatform(project.Root, parsed.OS); err != nil {
+				project.Out.Warnf("%s did not add platform '%s': %v", a.Name(), parsed.OS, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (a *v1Language) AddReleaseAsset(ctx context.Context, project *Project, assetType string) error {
+	if err := checkV1(ctx, a.Name(), a.fsys); err != nil {
+		return err
+	}
+	return capture(project, func() error {
+		return a.LanguageProvider.AddReleaseAsset(project.Root, assetType)
+	})
+}
+
+// RemoveReleaseAsset fails, the first version cannot remove release assets
+func (a *v1Language) RemoveReleaseAsset(ctx context.Context, project *Project, assetType string) error {
+	return fmt.Errorf("language provider '%s' cannot remove release assets", a.Name())
+}
+
+// releaseSettings returns the settings the first version renders release
+// scripts with
+func releaseSettings(project *Project) map[string]interface{} {
+	return map[string]interface{}{
+		"project_type": project.Config.ProjectType,
+		"module_path":  project.Config.ModulePath,
+	}
+}
+
+// v1CI adapts a CI provider of the first version
+type v1CI struct {
+	plugin.CIProvider
+	fsys vfs.FS
+}
+
+// CIFromV1 adapts a CI provider of the first version, like FromV1
+func CIFromV1(p plugin.CIProvider) CIProvider {
+	return &v1CI{CIProvider: p, fsys: vfs.OS}
+}
+
+// SetFilesystem sets the filesystem the provider must write to, which has to
+// be vfs.OS for its operations to run
+func (a *v1CI) SetFilesystem(fsys vfs.FS) {
+	a.fsys = fsys
+}
+
+func (a *v1CI) GenerateWorkflows(ctx context.Context, project *Project) error {
+	if err := checkV1(ctx, a.Name(), a.fsys); err != nil {
+		return err
+	}
+	cfg := project.Config
+	return capture(project, func() error {
+		return a.CIProvider.GenerateWorkflows(project.Root, cfg.Language, cfg.ProjectType, Settings(cfg))
+	})
+}
+
+// RenderWorkflows returns no file, the first version cannot render without writing
+func (a *v1CI) RenderWorkflows(ctx context.Context, project *Project) ([]plugin.GeneratedFile, error) {
+	return nil, ctx.Err()
+}
+
+// checkV1 refuses to run an operation of a provider of the first version
+// when it is cancelled, or when its writes would escape the filesystem it was
+// given, such as the overlay of --dry-run or of an open transaction
+func checkV1(ctx context.Context, name string, fsys vfs.FS) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if fsys != vfs.OS {
+		return fmt.Errorf("provider '%s' writes its files straight to the disk and cannot run with --dry-run or in a transaction", name)
+	}
+	return nil
+}
+
+// capture runs an operation of a provider of the first version, passing the
+// lines it prints to the standard output on to the output of the project
+func capture(project *Project, run func() error) error {
+	if project.Out == nil {
+		return run()
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return run()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			project.Out.Infof("%s", scanner.Text())
+		}
+	}()
+
+	// Restore the standard output even when the provider panics
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+		w.Close()
+		<-done
+		r.Close()
+	}()
+	return run()
+}
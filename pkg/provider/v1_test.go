@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/caezarr-oss/scotter/pkg/config"
+	"github.com/caezarr-oss/scotter/pkg/plugin"
+	"github.com/caezarr-oss/scotter/pkg/vfs"
+)
+
+// stubV1 is a language provider of the first version recording its calls
+type stubV1 struct {
+	calls  []string
+	panics bool
+}
+
+func (s *stubV1) Name() string                    { return "stub" }
+func (s *stubV1) SupportedProjectTypes() []string { return []string{"default"} }
+
+func (s *stubV1) Initialize(projectName, projectType string, config map[string]interface{}) error {
+	s.calls = append(s.calls, "Initialize "+projectName+" "+projectType)
+	fmt.Println("initialized", projectName)
+	if s.panics {
+		panic("initialize failed")
+	}
+	return nil
+}
+
+func (s *stubV1) GenerateReleaseScript(projectPath string, config map[string]interface{}) error {
+	s.calls = append(s.calls, "GenerateReleaseScript "+projectPath)
+	return nil
+}
+
+func (s *stubV1) AddPlatform(projectPath, platform string) error {
+	s.calls = append(s.calls, "AddPlatform "+projectPath+" "+platform)
+	if platform == "linux" {
+		return fmt.Errorf("platform 'linux' already exists")
+	}
+	return nil
+}
+
+func (s *stubV1) AddReleaseAsset(projectPath, assetType string) error {
+	s.calls = append(s.calls, "AddReleaseAsset "+projectPath+" "+assetType)
+	return nil
+}
+
+func (s *stubV1) IsSupportedPlatform(platform string) bool {
+	return platform == "linux" || platform == "darwin"
+}
+
+func (s *stubV1) IsSupportedArchitecture(arch string) bool {
+	return arch == "amd64" || arch == "arm64"
+}
+
+func (s *stubV1) IsSupportedReleaseAsset(assetType string) bool { return assetType == "checksum" }
+func (s *stubV1) GetSupportedPlatforms() []string               { return []string{"linux", "darwin"} }
+func (s *stubV1) GetSupportedArchitectures() []string           { return []string{"amd64", "arm64"} }
+func (s *stubV1) GetSupportedReleaseAssets() []string           { return []string{"checksum"} }
+
+// recordedOutput records the messages of a provider
+type recordedOutput struct {
+	infos, warnings []string
+}
+
+func (o *recordedOutput) Infof(format string, args ...interface{}) {
+	o.infos = append(o.infos, fmt.Sprintf(format, args...))
+}
+
+func (o *recordedOutput) Warnf(format string, args ...interface{}) {
+	o.warnings = append(o.warnings, fmt.Sprintf(format, args...))
+}
+
+func newStubProject() (*Project, *recordedOutput) {
+	out := &recordedOutput{}
+	return &Project{
+		Root:   "/work/app",
+		Config: &config.Config{ProjectName: "app", ProjectType: "default", Language: "stub"},
+		Out:    out,
+	}, out
+}
+
+func TestFromV1Registers(t *testing.T) {
+	loader := plugin.NewPluginLoader()
+	loader.RegisterLanguageProvider(FromV1(&stubV1{}))
+
+	if _, err := Language(loader, "stub"); err != nil {
+		t.Fatalf("Language() error = %v", err)
+	}
+}
+
+func TestFromV1PassesProjectRoot(t *testing.T) {
+	stub := &stubV1{}
+	adapted := FromV1(stub)
+	project, _ := newStubProject()
+	ctx := context.Background()
+
+	if err := adapted.Initialize(ctx, project); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := adapted.GenerateReleaseScript(ctx, project); err != nil {
+		t.Fatalf("GenerateReleaseScript() error = %v", err)
+	}
+	if err := adapted.AddReleaseAsset(ctx, project, "checksum"); err != nil {
+		t.Fatalf("AddReleaseAsset() error = %v", err)
+	}
+
+	want := []string{
+		"Initialize /work/app default",
+		"GenerateReleaseScript /work/app",
+		"AddReleaseAsset /work/app checksum",
+	}
+	if !reflect.DeepEqual(stub.calls, want) {
+		t.Errorf("calls = %q, want %q", stub.calls, want)
+	}
+}
+
+func TestFromV1CapturesOutput(t *testing.T) {
+	project, out := newStubProject()
+
+	if err := FromV1(&stubV1{}).Initialize(context.Background(), project); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if want := []string{"initialized /work/app"}; !reflect.DeepEqual(out.infos, want) {
+		t.Errorf("infos = %q, want %q", out.infos, want)
+	}
+}
+
+func TestFromV1Targets(t *testing.T) {
+	adapted := FromV1(&stubV1{})
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"linux/amd64", true},
+		{"darwin/arm64", true},
+		{"windows/amd64", false},
+		{"linux/386", false},
+		{"linux/arm64/v8.0", false},
+		{"linux", false},
+	}
+	for _, tt := range tests {
+		if got := adapted.IsSupportedTarget(tt.target); got != tt.want {
+			t.Errorf("IsSupportedTarget(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+
+	want := []string{"linux/amd64", "linux/arm64", "darwin/amd64", "darwin/arm64"}
+	if got := adapted.GetSupportedTargets(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSupportedTargets() = %q, want %q", got, want)
+	}
+}
+
+func TestFromV1SetTargets(t *testing.T) {
+	stub := &stubV1{}
+	project, out := newStubProject()
+
+	targets := []string{"linux/amd64", "linux/arm64", "darwin/arm64"}
+	if err := FromV1(stub).SetTargets(context.Background(), project, targets); err != nil {
+		t.Fatalf("SetTargets() error = %v", err)
+	}
+
+	want := []string{"AddPlatform /work/app linux", "AddPlatform /work/app darwin"}
+	if !reflect.DeepEqual(stub.calls, want) {
+		t.Errorf("calls = %q, want %q", stub.calls, want)
+	}
+	if len(out.warnings) != 1 {
+		t.Errorf("warnings = %q, want the refused linux platform", out.warnings)
+	}
+}
+
+func TestFromV1Cancelled(t *testing.T) {
+	stub := &stubV1{}
+	project, _ := newStubProject()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := FromV1(stub).Initialize(ctx, project); err == nil {
+		t.Error("Initialize() error = nil, want the context error")
+	}
+	if len(stub.calls) != 0 {
+		t.Errorf("calls = %q, want none", stub.calls)
+	}
+}
+
+func TestFromV1RefusesOtherFilesystems(t *testing.T) {
+	stub := &stubV1{}
+	adapted := FromV1(stub)
+	adapted.SetFilesystem(vfs.NewOverlay(vfs.OS))
+	project, _ := newStubProject()
+	ctx := context.Background()
+
+	if err := adapted.Initialize(ctx, project); err == nil {
+		t.Error("Initialize() error = nil, want the filesystem refused")
+	}
+	if err := adapted.AddReleaseAsset(ctx, project, "checksum"); err == nil {
+		t.Error("AddReleaseAsset() error = nil, want the filesystem refused")
+	}
+	if len(stub.calls) != 0 {
+		t.Errorf("calls = %q, want none", stub.calls)
+	}
+
+	ci := CIFromV1(stubCIV1{})
+	ci.SetFilesystem(vfs.NewOverlay(vfs.OS))
+	if err := ci.GenerateWorkflows(ctx, project); err == nil {
+		t.Error("GenerateWorkflows() error = nil, want the filesystem refused")
+	}
+}
+
+func TestFromV1RestoresStdoutOnPanic(t *testing.T) {
+	stdout := os.Stdout
+	project, _ := newStubProject()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Initialize() did not panic")
+			}
+		}()
+		FromV1(&stubV1{panics: true}).Initialize(context.Background(), project)
+	}()
+	if os.Stdout != stdout {
+		os.Stdout = stdout
+		t.Error("os.Stdout is still redirected after the panic")
+	}
+}
+
+// stubCIV1 is a CI provider of the first version failing if it runs
+type stubCIV1 struct{}
+
+func (stubCIV1) Name() string                 { return "stub-ci" }
+func (stubCIV1) SupportedLanguages() []string { return []string{"stub"} }
+
+func (stubCIV1) GenerateWorkflows(projectPath, language, projectType string, config map[string]interface{}) error {
+	return fmt.Errorf("generated workflows in %s", projectPath)
+}
@@ -0,0 +1,191 @@
+// Package scerrors defines the errors Scotter commands and providers fail
+// with. Each kind of failure has a sentinel to test with errors.Is, a
+// structured type to inspect with errors.As, and a stable process exit code.
+package scerrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinels of the kinds of failure
+var (
+	// ErrAlreadyExists reports a file or a configuration entry that is already there
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrUnsupportedTarget reports a platform, architecture or target the
+	// language provider cannot build for, or a release asset type it cannot
+	// publish
+	ErrUnsupportedTarget = errors.New("unsupported target")
+
+	// ErrProviderNotFound reports a language or CI provider that is not registered
+	ErrProviderNotFound = errors.New("provider not found")
+
+	// ErrConfigInvalid reports a configuration file with problems
+	ErrConfigInvalid = errors.New("invalid configuration")
+
+	// ErrToolMissing reports an external command that is not installed
+	ErrToolMissing = errors.New("tool missing")
+
+	// ErrInterrupted reports a command interrupted by the user
+	ErrInterrupted = errors.New("interrupted")
+
+	// ErrConflict reports files merged with conflict markers left to resolve
+	ErrConflict = errors.New("conflict")
+)
+
+// Exit codes of the process, by kind of failure; they are part of the
+// command-line interface and never change
+const (
+	ExitError             = 1
+	ExitConfigInvalid     = 3
+	ExitProviderNotFound  = 4
+	ExitUnsupportedTarget = 5
+	ExitAlreadyExists     = 6
+	ExitToolMissing       = 7
+	ExitConflict          = 8
+	ExitInterrupted       = 130
+)
+
+// exitCodes maps the sentinels to their exit code
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{ErrInterrupted, ExitInterrupted},
+	{ErrConfigInvalid, ExitConfigInvalid},
+	{ErrProviderNotFound, ExitProviderNotFound},
+	{ErrUnsupportedTarget, ExitUnsupportedTarget},
+	{ErrAlreadyExists, ExitAlreadyExists},
+	{ErrToolMissing, ExitToolMissing},
+	{ErrConflict, ExitConflict},
+}
+
+// ExitCode returns the exit code of the process failing with err, 0 when
+// err is nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	for _, e := range exitCodes {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+	return ExitError
+}
+
+// AlreadyExistsError reports a file or configuration entry that already exists
+type AlreadyExistsError struct {
+	// Kind is the kind of entry, such as file, platform or target
+	Kind string
+
+	// Name is the name of the entry
+	Name string
+
+	// Path is the file holding the entry, or the file itself
+	Path string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	if e.Kind == "file" {
+		return fmt.Sprintf("%s already exists", e.Name)
+	}
+	return fmt.Sprintf("%s '%s' already exists", e.Kind, e.Name)
+}
+
+// Is reports whether target is ErrAlreadyExists
+func (e *AlreadyExistsError) Is(target error) bool {
+	return target == ErrAlreadyExists
+}
+
+// UnsupportedTargetError reports a platform, architecture, target, variant or
+// release asset type a language provider does not support
+type UnsupportedTargetError struct {
+	// Kind is platform, architecture, target, variant or release asset type
+	Kind string
+
+	// Value is the unsupported value
+	Value string
+
+	// Provider is the name of the language provider
+	Provider string
+
+	// Supported lists the supported values, when they help choosing one
+	Supported []string
+}
+
+func (e *UnsupportedTargetError) Error() string {
+	message := fmt.Sprintf("%s '%s' is not supported by %s", e.Kind, e.Value, e.Provider)
+	if len(e.Supported) > 0 {
+		message += ", expected one of " + strings.Join(e.Supported, ", ")
+	}
+	return message
+}
+
+// Is reports whether target is ErrUnsupportedTarget
+func (e *UnsupportedTargetError) Is(target error) bool {
+	return target == ErrUnsupportedTarget
+}
+
+// ProviderNotFoundError reports a provider that is not registered
+type ProviderNotFoundError struct {
+	// Kind is language or CI
+	Kind string
+
+	// Provider is the name of the missing provider
+	Provider string
+}
+
+func (e *ProviderNotFoundError) Error() string {
+	return fmt.Sprintf("%s provider '%s' not found", e.Kind, e.Provider)
+}
+
+// Is reports whether target is ErrProviderNotFound
+func (e *ProviderNotFoundError) Is(target error) bool {
+	return target == ErrProviderNotFound
+}
+
+// ToolMissingError reports an external command that could not be found
+type ToolMissingError struct {
+	// Tool is the name of the command
+	Tool string
+
+	// Purpose tells what the tool is needed for
+	Purpose string
+
+	// Err is the error of the lookup
+	Err error
+}
+
+func (e *ToolMissingError) Error() string {
+	return fmt.Sprintf("%s is required to %s: %v", e.Tool, e.Purpose, e.Err)
+}
+
+// Unwrap returns the error of the lookup
+func (e *ToolMissingError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrToolMissing
+func (e *ToolMissingError) Is(target error) bool {
+	return target == ErrToolMissing
+}
+
+// ConflictError reports files written with conflict markers. The command
+// still succeeded in writing its changes, which are kept.
+type ConflictError struct {
+	// Files lists the paths of the conflicted files
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%d file(s) have conflicts, resolve the conflict markers by hand: %s",
+		len(e.Files), strings.Join(e.Files, ", "))
+}
+
+// Is reports whether target is ErrConflict
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
@@ -0,0 +1,259 @@
+package vfs
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of change recorded by an Overlay
+const (
+	Created  = "created"
+	Modified = "modified"
+	Deleted  = "deleted"
+)
+
+// Change is the difference an Overlay makes to a file of its base
+type Change struct {
+	Path   string
+	Kind   string
+	Before []byte
+	After  []byte
+	Perm   fs.FileMode
+}
+
+// Overlay records writes and removals in memory, over a base filesystem it
+// reads through and never changes
+type Overlay struct {
+	base  FS
+	files map[string]*entry
+	dirs  map[string]fs.FileMode
+}
+
+// entry is a file of the overlay, or its removal
+type entry struct {
+	content []byte
+	perm    fs.FileMode
+	removed bool
+}
+
+// NewOverlay creates an empty overlay over base
+func NewOverlay(base FS) *Overlay {
+	return &Overlay{
+		base:  base,
+		files: make(map[string]*entry),
+		dirs:  make(map[string]fs.FileMode),
+	}
+}
+
+// ReadFile reads a file of the overlay, or else of the base
+func (o *Overlay) ReadFile(name string) ([]byte, error) {
+	path := abs(name)
+	if e, ok := o.files[path]; ok {
+		if e.removed {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		return append([]byte(nil), e.content...), nil
+	}
+	return o.base.ReadFile(name)
+}
+
+// WriteFile records the content of a file
+func (o *Overlay) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	path := abs(name)
+	if _, ok := o.dirs[path]; ok {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	}
+	o.files[path] = &entry{content: append([]byte(nil), data...), perm: perm}
+	return nil
+}
+
+// MkdirAll records a directory and its missing parents
+func (o *Overlay) MkdirAll(name string, perm fs.FileMode) error {
+	path := abs(name)
+	if e, ok := o.files[path]; ok && !e.removed {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if info, err := o.base.Stat(path); err == nil && info.IsDir() {
+		return nil
+	}
+	o.dirs[path] = perm
+	return nil
+}
+
+// Stat describes a file or directory of the overlay, or else of the base
+func (o *Overlay) Stat(name string) (fs.FileInfo, error) {
+	path := abs(name)
+	if e, ok := o.files[path]; ok {
+		if e.removed {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+		return fileInfo{name: filepath.Base(path), size: int64(len(e.content)), mode: e.perm}, nil
+	}
+	if o.hasDir(path) {
+		return fileInfo{name: filepath.Base(path), mode: fs.ModeDir | 0755}, nil
+	}
+	return o.base.Stat(name)
+}
+
+// ReadDir lists a directory, merging the overlay with the base
+func (o *Overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	path := abs(name)
+	entries := make(map[string]fs.DirEntry)
+
+	base, err := o.base.ReadDir(name)
+	if err != nil && !(os.IsNotExist(err) && o.hasDir(path)) {
+		return nil, err
+	}
+	for _, e := range base {
+		entries[e.Name()] = e
+	}
+
+	for file, e := range o.files {
+		if filepath.Dir(file) != path {
+			continue
+		}
+		if e.removed {
+			delete(entries, filepath.Base(file))
+			continue
+		}
+		entries[filepath.Base(file)] = fs.FileInfoToDirEntry(fileInfo{name: filepath.Base(file), size: int64(len(e.content)), mode: e.perm})
+	}
+	for _, child := range o.children(path) {
+		entries[child] = fs.FileInfoToDirEntry(fileInfo{name: child, mode: fs.ModeDir | 0755})
+	}
+
+	list := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list, nil
+}
+
+// Remove records the removal of a file
+func (o *Overlay) Remove(name string) error {
+	path := abs(name)
+	if e, ok := o.files[path]; ok && !e.removed {
+		if _, err := o.base.Stat(path); err != nil {
+			delete(o.files, path)
+		} else {
+			o.files[path] = &entry{removed: true}
+		}
+		return nil
+	}
+	if _, ok := o.dirs[path]; ok {
+		delete(o.dirs, path)
+		return nil
+	}
+	if _, err := o.base.Stat(path); err != nil {
+		return err
+	}
+	o.files[path] = &entry{removed: true}
+	return nil
+}
+
+// Dirs returns the directories recorded by the overlay, sorted
+func (o *Overlay) Dirs() []string {
+	dirs := make([]string, 0, len(o.dirs))
+	for dir := range o.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// Changes returns the files the overlay creates, modifies or deletes in its
+// base, sorted by path; writes leaving a file as it was are left out
+func (o *Overlay) Changes() []Change {
+	var changes []Change
+	for path, e := range o.files {
+		before, err := o.base.ReadFile(path)
+		existed := err == nil
+
+		switch {
+		case e.removed && existed:
+			changes = append(changes, Change{Path: path, Kind: Deleted, Before: before})
+		case e.removed:
+		case !existed:
+			changes = append(changes, Change{Path: path, Kind: Created, After: e.content, Perm: e.perm})
+		case !bytes.Equal(before, e.content):
+			changes = append(changes, Change{Path: path, Kind: Modified, Before: before, After: e.content, Perm: e.perm})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// hasDir reports whether a directory is recorded, or holds recorded entries
+func (o *Overlay) hasDir(path string) bool {
+	if _, ok := o.dirs[path]; ok {
+		return true
+	}
+	return len(o.children(path)) > 0 || o.holdsFiles(path)
+}
+
+// holdsFiles reports whether files are recorded below a directory
+func (o *Overlay) holdsFiles(path string) bool {
+	prefix := path + string(filepath.Separator)
+	for file, e := range o.files {
+		if !e.removed && strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// children returns the names of the directories recorded right below path,
+// including the parents of recorded directories and files
+func (o *Overlay) children(path string) []string {
+	prefix := path + string(filepath.Separator)
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if rest, ok := strings.CutPrefix(p, prefix); ok {
+			name, _, _ := strings.Cut(rest, string(filepath.Separator))
+			seen[name] = true
+		}
+	}
+	for dir := range o.dirs {
+		add(dir)
+	}
+	for file, e := range o.files {
+		if !e.removed {
+			add(filepath.Dir(file))
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// abs returns the absolute form of a path, the key of the overlay entries
+func abs(name string) string {
+	if path, err := filepath.Abs(name); err == nil {
+		return path
+	}
+	return filepath.Clean(name)
+}
+
+// fileInfo describes a file or directory of the overlay
+type fileInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i fileInfo) ModTime() time.Time { return time.Time{} }
+func (i fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i fileInfo) Sys() interface{}   { return nil }
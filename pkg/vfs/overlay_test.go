@@ -0,0 +1,140 @@
+package vfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newTestOverlay returns an overlay over a directory holding kept.txt and
+// removed.txt
+func newTestOverlay(t *testing.T) (*Overlay, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"kept.txt", "removed.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewOverlay(OS), dir
+}
+
+func TestOverlayStat(t *testing.T) {
+	o, dir := newTestOverlay(t)
+	if err := o.WriteFile(filepath.Join(dir, "sub", "new.txt"), []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Remove(filepath.Join(dir, "removed.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := o.Stat(filepath.Join(dir, "sub", "new.txt"))
+	if err != nil {
+		t.Fatalf("Stat(new.txt) error = %v", err)
+	}
+	if info.Size() != 3 || info.Mode() != 0600 {
+		t.Errorf("Stat(new.txt) = size %d, mode %v, want size 3, mode 0600", info.Size(), info.Mode())
+	}
+
+	if info, err := o.Stat(filepath.Join(dir, "sub")); err != nil || !info.IsDir() {
+		t.Errorf("Stat(sub) = %v, %v, want the parent directory of a staged file", info, err)
+	}
+	if _, err := o.Stat(filepath.Join(dir, "removed.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(removed.txt) error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := o.Stat(filepath.Join(dir, "kept.txt")); err != nil {
+		t.Errorf("Stat(kept.txt) error = %v, want the file of the base", err)
+	}
+}
+
+func TestOverlayReadDir(t *testing.T) {
+	o, dir := newTestOverlay(t)
+	if err := o.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.MkdirAll(filepath.Join(dir, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Remove(filepath.Join(dir, "removed.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := o.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"a", "kept.txt", "new.txt"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ReadDir() = %q, want %q", names, want)
+	}
+
+	// A staged directory missing from the base can be listed
+	entries, err = o.ReadDir(filepath.Join(dir, "a"))
+	if err != nil || len(entries) != 1 || entries[0].Name() != "b" || !entries[0].IsDir() {
+		t.Errorf("ReadDir(a) = %v, %v, want the staged directory b", entries, err)
+	}
+}
+
+func TestOverlayRemove(t *testing.T) {
+	o, dir := newTestOverlay(t)
+	staged := filepath.Join(dir, "staged.txt")
+	if err := o.WriteFile(staged, []byte("staged"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing a staged file that the base lacks leaves no change
+	if err := o.Remove(staged); err != nil {
+		t.Fatalf("Remove(staged.txt) error = %v", err)
+	}
+	if _, err := o.ReadFile(staged); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile(staged.txt) error = %v, want fs.ErrNotExist", err)
+	}
+
+	// Removing a file of the base records its deletion without touching it
+	removed := filepath.Join(dir, "removed.txt")
+	if err := o.Remove(removed); err != nil {
+		t.Fatalf("Remove(removed.txt) error = %v", err)
+	}
+	if _, err := os.Stat(removed); err != nil {
+		t.Errorf("base file removed: %v", err)
+	}
+
+	if err := o.Remove(filepath.Join(dir, "missing.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove(missing.txt) error = %v, want fs.ErrNotExist", err)
+	}
+
+	changes := o.Changes()
+	if len(changes) != 1 || changes[0].Path != removed || changes[0].Kind != Deleted {
+		t.Errorf("Changes() = %+v, want the deletion of removed.txt", changes)
+	}
+}
+
+func TestOverlayChanges(t *testing.T) {
+	o, dir := newTestOverlay(t)
+	kept := filepath.Join(dir, "kept.txt")
+	created := filepath.Join(dir, "created.txt")
+	if err := o.WriteFile(kept, []byte("kept.txt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.WriteFile(filepath.Join(dir, "removed.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.WriteFile(created, []byte("created"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, change := range o.Changes() {
+		got = append(got, filepath.Base(change.Path)+" "+change.Kind)
+	}
+	// Writing the same content is no change
+	if want := []string{"created.txt created", "removed.txt modified"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() = %q, want %q", got, want)
+	}
+}
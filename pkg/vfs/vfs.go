@@ -0,0 +1,92 @@
+// Package vfs defines the filesystem providers read and write project files
+// through, so that their changes can be staged or previewed
+package vfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FS is a writable filesystem addressed by OS paths
+type FS interface {
+	// ReadFile reads a whole file
+	ReadFile(name string) ([]byte, error)
+
+	// WriteFile writes a whole file, creating it with perm if needed
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+
+	// MkdirAll creates a directory and its missing parents
+	MkdirAll(name string, perm fs.FileMode) error
+
+	// Stat describes a file or directory
+	Stat(name string) (fs.FileInfo, error)
+
+	// ReadDir lists a directory, sorted by name
+	ReadDir(name string) ([]fs.DirEntry, error)
+
+	// Remove removes a file or an empty directory
+	Remove(name string) error
+}
+
+// OS is the filesystem of the operating system
+var OS FS = osFS{}
+
+// osFS implements FS with the os package
+type osFS struct{}
+
+func (osFS) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+func (osFS) MkdirAll(name string, perm fs.FileMode) error { return os.MkdirAll(name, perm) }
+func (osFS) Stat(name string) (fs.FileInfo, error)        { return os.Stat(name) }
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error)   { return os.ReadDir(name) }
+func (osFS) Remove(name string) error                     { return os.Remove(name) }
+
+// WalkDir walks the tree rooted at root, calling fn for each file and
+// directory in lexical order, like filepath.WalkDir
+func WalkDir(fsys FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkDir walks the tree below a directory entry
+func walkDir(fsys FS, path string, entry fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, entry, nil); err != nil || !entry.IsDir() {
+		if err == filepath.SkipDir && entry.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		if err = fn(path, entry, err); err != nil {
+			if err == filepath.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, child := range entries {
+		if err := walkDir(fsys, filepath.Join(path, child.Name()), child, fn); err != nil {
+			if err == filepath.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
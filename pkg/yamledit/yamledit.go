@@ -0,0 +1,481 @@
+// Package yamledit edits YAML documents in place while preserving comments,
+// blank lines and formatting of everything that is not modified.
+//
+// Nodes are located with yaml.v3 and the edits are applied as line splices on
+// the original text, so a hand-written file only changes where it is edited.
+//
+// The mappings being edited must use block style. Set, SetYAML and Delete
+// return an error for a mapping written in flow style, such as the items of
+// archives: [{format: zip}], rather than splicing lines into it; a key whose
+// value is in flow style can still be replaced or deleted as a whole. Flow
+// sequences are rewritten whole by Append and Remove.
+package yamledit
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a YAML document being edited
+type Document struct {
+	lines []string
+	root  yaml.Node
+}
+
+// Parse parses a YAML document; an empty input yields an empty mapping
+func Parse(data []byte) (*Document, error) {
+	text := strings.TrimSuffix(string(data), "\n")
+	d := &Document{}
+	if text != "" {
+		d.lines = strings.Split(text, "\n")
+	}
+	if err := d.reparse(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Bytes returns the edited document
+func (d *Document) Bytes() []byte {
+	if len(d.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(d.lines, "\n") + "\n")
+}
+
+// Root returns the top-level mapping of the document
+func (d *Document) Root() *yaml.Node {
+	if len(d.root.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	return d.root.Content[0]
+}
+
+// Get returns the node at path, made of mapping keys (string) and sequence
+// indexes (int), or nil when it does not exist
+func (d *Document) Get(path ...interface{}) *yaml.Node {
+	node := d.Root()
+	for _, element := range path {
+		switch e := element.(type) {
+		case string:
+			_, value := lookup(node, e)
+			if value == nil {
+				return nil
+			}
+			node = value
+		case int:
+			if node.Kind != yaml.SequenceNode || e < 0 || e >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[e]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// Set sets key in the mapping at path to value, replacing the previous value
+// in place or appending the key at the end of the mapping; the mappings
+// missing along path are created
+func (d *Document) Set(path []interface{}, key string, value *yaml.Node) error {
+	// Keep the comment following a replaced scalar
+	_, previous := lookup(d.Get(path...), key)
+	if previous != nil && previous.LineComment != "" && value.Kind == yaml.ScalarNode && value.LineComment == "" {
+		commented := *value
+		commented.LineComment = previous.LineComment
+		value = &commented
+	}
+
+	return d.setBlock(path, key, func(indent int) ([]string, error) {
+		return encodeKey(key, value, indent)
+	})
+}
+
+// SetYAML sets key in the mapping at path to a block YAML snippet, written
+// verbatim so that its layout, such as folded scalars, is kept; the mappings
+// missing along path are created
+func (d *Document) SetYAML(path []interface{}, key, snippet string) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(snippet), &node); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	return d.setBlock(path, key, func(indent int) ([]string, error) {
+		line, err := keyLine(key, indent)
+		if err != nil {
+			return nil, err
+		}
+		block := []string{line}
+		for _, line := range strings.Split(strings.TrimRight(snippet, "\n"), "\n") {
+			if line != "" {
+				line = strings.Repeat(" ", indent+2) + line
+			}
+			block = append(block, line)
+		}
+		return block, nil
+	})
+}
+
+// setBlock replaces the entry of key in the mapping at path with the lines
+// rendered for its indentation, or appends them at the end of the mapping
+func (d *Document) setBlock(path []interface{}, key string, render func(indent int) ([]string, error)) error {
+	mapping := d.Get(path...)
+	if mapping == nil {
+		// Set the first missing mapping of the path, holding the others
+		parent := len(path) - 1
+		for parent > 0 && d.Get(path[:parent]...) == nil {
+			parent--
+		}
+		missing, ok := path[parent].(string)
+		if !ok {
+			return fmt.Errorf("%s is not a mapping", formatPath(path[:parent+1]))
+		}
+		return d.setBlock(path[:parent], missing, func(indent int) ([]string, error) {
+			var block []string
+			for _, element := range path[parent:] {
+				name, ok := element.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s is not a mapping", formatPath(path))
+				}
+				line, err := keyLine(name, indent)
+				if err != nil {
+					return nil, err
+				}
+				block = append(block, line)
+				indent += 2
+			}
+			lines, err := render(indent)
+			if err != nil {
+				return nil, err
+			}
+			return append(block, lines...), nil
+		})
+	}
+	if err := checkBlockMapping(mapping, path); err != nil {
+		return err
+	}
+
+	keyNode, valueNode := lookup(mapping, key)
+	if keyNode != nil {
+		start, end := d.span(keyNode, valueNode)
+		indent := keyNode.Column - 1
+		block, err := render(indent)
+		if err != nil {
+			return err
+		}
+		// Keep the dash of a key opening a sequence item
+		block[0] = d.lines[start][:indent] + block[0][indent:]
+		d.splice(start, end, block)
+		return d.reparse()
+	}
+
+	// Append the key after the last key of the mapping
+	indent, at := 0, len(d.lines)
+	if len(mapping.Content) > 0 {
+		first := mapping.Content[0]
+		indent = first.Column - 1
+		_, at = d.span(mapping.Content[len(mapping.Content)-2], mapping.Content[len(mapping.Content)-1])
+		at++
+	}
+	block, err := render(indent)
+	if err != nil {
+		return err
+	}
+	if indent == 0 && len(d.lines) > 0 {
+		// Separate top-level sections with a blank line
+		at = len(d.lines)
+		for at > 0 && strings.TrimSpace(d.lines[at-1]) == "" {
+			at--
+		}
+		block = append([]string{""}, block...)
+	}
+	d.splice(at, at-1, block)
+	return d.reparse()
+}
+
+// Delete removes key, its value and its head comment from the mapping at path
+func (d *Document) Delete(path []interface{}, key string) (bool, error) {
+	mapping := d.Get(path...)
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return false, nil
+	}
+	if err := checkBlockMapping(mapping, path); err != nil {
+		return false, err
+	}
+
+	keyNode, valueNode := lookup(mapping, key)
+	if keyNode == nil {
+		return false, nil
+	}
+
+	start, end := d.span(keyNode, valueNode)
+	for start > 0 && isComment(d.lines[start-1]) && d.indent(start-1) == keyNode.Column-1 {
+		start--
+	}
+	d.splice(start, end, nil)
+
+	// Avoid leaving two blank lines where the section was
+	if start > 0 && start < len(d.lines) && strings.TrimSpace(d.lines[start-1]) == "" && strings.TrimSpace(d.lines[start]) == "" {
+		d.splice(start, start, nil)
+	}
+	for len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) == "" {
+		d.lines = d.lines[:len(d.lines)-1]
+	}
+	return true, d.reparse()
+}
+
+// Append adds a scalar to the sequence at path unless it is already present
+func (d *Document) Append(path []interface{}, value string) error {
+	seq := d.Get(path...)
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s is not a sequence", formatPath(path))
+	}
+	if indexOf(seq, value) >= 0 {
+		return nil
+	}
+
+	if seq.Style&yaml.FlowStyle != 0 || len(seq.Content) == 0 {
+		updated := cloneSequence(seq)
+		updated.Content = append(updated.Content, scalar(value))
+		return d.replaceValue(path, updated)
+	}
+
+	last := seq.Content[len(seq.Content)-1]
+	dash := d.dashIndent(last)
+	_, end := d.itemSpan(last, dash)
+	d.splice(end+1, end, []string{strings.Repeat(" ", dash) + "- " + FormatScalar(value)})
+	return d.reparse()
+}
+
+// Remove removes a scalar from the sequence at path and reports whether it was present
+func (d *Document) Remove(path []interface{}, value string) (bool, error) {
+	seq := d.Get(path...)
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return false, nil
+	}
+	i := indexOf(seq, value)
+	if i < 0 {
+		return false, nil
+	}
+
+	if seq.Style&yaml.FlowStyle != 0 || len(seq.Content) == 1 {
+		updated := cloneSequence(seq)
+		updated.Content = append(updated.Content[:i:i], updated.Content[i+1:]...)
+		if len(updated.Content) == 0 {
+			updated.Style = yaml.FlowStyle
+		}
+		return true, d.replaceValue(path, updated)
+	}
+
+	item := seq.Content[i]
+	start, end := d.itemSpan(item, d.dashIndent(item))
+	d.splice(start, end, nil)
+	return true, d.reparse()
+}
+
+// replaceValue replaces the value at path, which must be a mapping value
+func (d *Document) replaceValue(path []interface{}, value *yaml.Node) error {
+	if len(path) == 0 {
+		return fmt.Errorf("cannot replace the document root")
+	}
+	key, ok := path[len(path)-1].(string)
+	if !ok {
+		return fmt.Errorf("%s is not a mapping value", formatPath(path))
+	}
+	return d.Set(path[:len(path)-1], key, value)
+}
+
+// span returns the first and last line of a mapping entry, trailing blank
+// lines and comments excluded
+func (d *Document) span(keyNode, valueNode *yaml.Node) (int, int) {
+	start := keyNode.Line - 1
+	indent := keyNode.Column - 1
+	blockSequence := valueNode != nil && valueNode.Kind == yaml.SequenceNode && valueNode.Style&yaml.FlowStyle == 0
+
+	end := len(d.lines) - 1
+	for i := start + 1; i < len(d.lines); i++ {
+		line := d.lines[i]
+		if strings.TrimSpace(line) == "" || isComment(line) || d.indent(i) > indent {
+			continue
+		}
+		// Block sequences may be indented at the level of their key
+		if blockSequence && d.indent(i) == indent && strings.HasPrefix(strings.TrimSpace(line), "-") {
+			continue
+		}
+		end = i - 1
+		break
+	}
+	return start, d.trimEnd(start, end, indent)
+}
+
+// itemSpan returns the first and last line of a block sequence item
+func (d *Document) itemSpan(item *yaml.Node, dash int) (int, int) {
+	start := item.Line - 1
+	end := len(d.lines) - 1
+	for i := start + 1; i < len(d.lines); i++ {
+		if strings.TrimSpace(d.lines[i]) == "" || isComment(d.lines[i]) || d.indent(i) > dash {
+			continue
+		}
+		end = i - 1
+		break
+	}
+	return start, d.trimEnd(start, end, dash)
+}
+
+// trimEnd drops the blank lines and outer comments ending a span
+func (d *Document) trimEnd(start, end, indent int) int {
+	for end > start && (strings.TrimSpace(d.lines[end]) == "" || (isComment(d.lines[end]) && d.indent(end) <= indent)) {
+		end--
+	}
+	return end
+}
+
+// dashIndent returns the indentation of the dash introducing a sequence item
+func (d *Document) dashIndent(item *yaml.Node) int {
+	line := d.lines[item.Line-1]
+	if i := strings.LastIndex(line[:item.Column-1], "-"); i >= 0 {
+		return i
+	}
+	return d.indent(item.Line - 1)
+}
+
+// indent returns the number of leading spaces of a line
+func (d *Document) indent(i int) int {
+	return len(d.lines[i]) - len(strings.TrimLeft(d.lines[i], " "))
+}
+
+// splice replaces lines start..end (inclusive) with block
+func (d *Document) splice(start, end int, block []string) {
+	lines := make([]string, 0, len(d.lines)+len(block))
+	lines = append(lines, d.lines[:start]...)
+	lines = append(lines, block...)
+	lines = append(lines, d.lines[end+1:]...)
+	d.lines = lines
+}
+
+// reparse refreshes node positions after an edit
+func (d *Document) reparse() error {
+	d.root = yaml.Node{}
+	if err := yaml.Unmarshal(d.Bytes(), &d.root); err != nil {
+		return err
+	}
+	if len(d.root.Content) > 0 && d.root.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("document root is not a mapping")
+	}
+	return nil
+}
+
+// encodeKey renders "key: value" as block YAML indented by indent spaces
+func encodeKey(key string, value *yaml.Node, indent int) ([]string, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{scalar(key), value}}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(mapping); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	prefix := strings.Repeat(" ", indent)
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return lines, nil
+}
+
+// checkBlockMapping reports an error unless node is a block mapping, the only
+// mappings the line splices can edit
+func checkBlockMapping(node *yaml.Node, path []interface{}) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a mapping", formatPath(path))
+	}
+	if node.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("%s is written in flow style, rewrite it in block style to edit it", formatPath(path))
+	}
+	return nil
+}
+
+// keyLine renders the "key:" line opening a block value indented by indent spaces
+func keyLine(key string, indent int) (string, error) {
+	lines, err := encodeKey(key, scalar(""), indent)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(lines[0], ` ""`), nil
+}
+
+// lookup returns the key and value nodes of a mapping entry
+func lookup(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// indexOf returns the index of a scalar in a sequence, or -1
+func indexOf(seq *yaml.Node, value string) int {
+	for i, item := range seq.Content {
+		if item.Kind == yaml.ScalarNode && item.Value == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// cloneSequence copies a sequence node without its position
+func cloneSequence(seq *yaml.Node) *yaml.Node {
+	clone := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: seq.Style}
+	for _, item := range seq.Content {
+		copied := *item
+		copied.Line, copied.Column = 0, 0
+		clone.Content = append(clone.Content, &copied)
+	}
+	return clone
+}
+
+// scalar creates a string scalar node
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// FormatScalar renders a string scalar the way the yaml encoder would
+func FormatScalar(value string) string {
+	lines, err := encodeKey("k", scalar(value), 0)
+	if err != nil || len(lines) != 1 {
+		return strconv.Quote(value)
+	}
+	return strings.TrimPrefix(lines[0], "k: ")
+}
+
+// isComment reports whether a line only holds a comment
+func isComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// formatPath renders a node path for error messages
+func formatPath(path []interface{}) string {
+	if len(path) == 0 {
+		return "document root"
+	}
+	parts := make([]string, len(path))
+	for i, element := range path {
+		parts[i] = fmt.Sprint(element)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,377 @@
+package yamledit
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const document = `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: ghcr.io
+  tags: [latest]
+`
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []interface{}
+		key   string
+		value *yaml.Node
+		want  string
+	}{
+		{
+			name:  "replaced scalar",
+			key:   "name",
+			value: scalar("other"),
+			want: `# Project settings
+name: other # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: ghcr.io
+  tags: [latest]
+`,
+		},
+		{
+			name:  "replaced nested scalar",
+			path:  []interface{}{"extra"},
+			key:   "registry",
+			value: scalar("docker.io"),
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: docker.io
+  tags: [latest]
+`,
+		},
+		{
+			name: "replaced sequence",
+			key:  "targets",
+			value: &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{
+				scalar("linux/amd64"), scalar("darwin/arm64"),
+			}},
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+  - darwin/arm64
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: ghcr.io
+  tags: [latest]
+`,
+		},
+		{
+			name:  "appended key",
+			path:  []interface{}{"extra"},
+			key:   "user",
+			value: scalar("acme"),
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: ghcr.io
+  tags: [latest]
+  user: acme
+`,
+		},
+		{
+			name:  "appended top-level key",
+			key:   "language",
+			value: scalar("go"),
+			want: document + `
+language: go
+`,
+		},
+		{
+			name:  "missing mappings",
+			path:  []interface{}{"extra", "docker", "auth"},
+			key:   "user",
+			value: scalar("acme"),
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: ghcr.io
+  tags: [latest]
+  docker:
+    auth:
+      user: acme
+`,
+		},
+		{
+			name:  "missing top-level mapping",
+			path:  []interface{}{"release"},
+			key:   "draft",
+			value: &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+			want: document + `
+release:
+  draft: true
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parse(t, document)
+			if err := doc.Set(tt.path, tt.key, tt.value); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if got := string(doc.Bytes()); got != tt.want {
+				t.Errorf("Set() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetNotMapping(t *testing.T) {
+	doc := parse(t, document)
+	for _, path := range [][]interface{}{{"name"}, {"targets"}, {"name", "first"}} {
+		if err := doc.Set(path, "key", scalar("value")); err == nil {
+			t.Errorf("Set(%v) error = nil, want a mapping required", path)
+		}
+	}
+	if got := string(doc.Bytes()); got != document {
+		t.Errorf("Set() changed the document to\n%s", got)
+	}
+}
+
+func TestSetYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []interface{}
+		key     string
+		snippet string
+		want    string
+	}{
+		{
+			name:    "replaced section",
+			key:     "extra",
+			snippet: "registry: docker.io\nnote: >\n  folded\n  text\n",
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  registry: docker.io
+  note: >
+    folded
+    text
+`,
+		},
+		{
+			name:    "appended section",
+			key:     "checksum",
+			snippet: "# Checksums of the archives\nname_template: checksums.txt\n",
+			want: document + `
+checksum:
+  # Checksums of the archives
+  name_template: checksums.txt
+`,
+		},
+		{
+			name:    "missing mappings",
+			path:    []interface{}{"extra", "docker"},
+			key:     "build",
+			snippet: "context: .\n",
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: ghcr.io
+  tags: [latest]
+  docker:
+    build:
+      context: .
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parse(t, document)
+			if err := doc.SetYAML(tt.path, tt.key, tt.snippet); err != nil {
+				t.Fatalf("SetYAML() error = %v", err)
+			}
+			if got := string(doc.Bytes()); got != tt.want {
+				t.Errorf("SetYAML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []interface{}
+		key   string
+		found bool
+		want  string
+	}{
+		{
+			name:  "key with head comment",
+			path:  []interface{}{"extra"},
+			key:   "registry",
+			found: true,
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+
+# Settings of the templates
+extra:
+  tags: [latest]
+`,
+		},
+		{
+			name:  "block sequence",
+			key:   "targets",
+			found: true,
+			want: `# Project settings
+name: demo # the name
+
+# Settings of the templates
+extra:
+  # Registry to push to
+  registry: ghcr.io
+  tags: [latest]
+`,
+		},
+		{
+			name:  "last section",
+			key:   "extra",
+			found: true,
+			want: `# Project settings
+name: demo # the name
+targets:
+  - linux/amd64
+`,
+		},
+		{
+			name: "missing key",
+			key:  "language",
+			want: document,
+		},
+		{
+			name: "missing mapping",
+			path: []interface{}{"release"},
+			key:  "draft",
+			want: document,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parse(t, document)
+			found, err := doc.Delete(tt.path, tt.key)
+			if err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if found != tt.found {
+				t.Errorf("Delete() = %v, want %v", found, tt.found)
+			}
+			if got := string(doc.Bytes()); got != tt.want {
+				t.Errorf("Delete() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyDocument(t *testing.T) {
+	doc := parse(t, "")
+	if err := doc.Set([]interface{}{"extra"}, "registry", scalar("ghcr.io")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, want := string(doc.Bytes()), "extra:\n  registry: ghcr.io\n"; got != want {
+		t.Errorf("Set() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func parse(t *testing.T, data string) *Document {
+	t.Helper()
+	doc, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return doc
+}
+
+func TestFlowStyle(t *testing.T) {
+	const flow = `archives: [{format: zip}]
+extra: {registry: ghcr.io}
+`
+
+	edits := map[string]func(doc *Document) error{
+		"Set in flow mapping": func(doc *Document) error {
+			return doc.Set([]interface{}{"extra"}, "user", scalar("acme"))
+		},
+		"Set in flow sequence item": func(doc *Document) error {
+			return doc.Set([]interface{}{"archives", 0}, "format", scalar("binary"))
+		},
+		"Set below flow mapping": func(doc *Document) error {
+			return doc.Set([]interface{}{"extra", "auth"}, "user", scalar("acme"))
+		},
+		"SetYAML in flow mapping": func(doc *Document) error {
+			return doc.SetYAML([]interface{}{"extra"}, "auth", "user: acme\n")
+		},
+		"Delete from flow mapping": func(doc *Document) error {
+			_, err := doc.Delete([]interface{}{"extra"}, "registry")
+			return err
+		},
+	}
+	for name, edit := range edits {
+		t.Run(name, func(t *testing.T) {
+			doc := parse(t, flow)
+			if err := edit(doc); err == nil || !strings.Contains(err.Error(), "flow style") {
+				t.Errorf("error = %v, want the flow style refused", err)
+			}
+			if got := string(doc.Bytes()); got != flow {
+				t.Errorf("document changed to\n%s", got)
+			}
+		})
+	}
+
+	// Values in flow style are replaced as a whole
+	doc := parse(t, flow)
+	if err := doc.SetYAML(nil, "archives", "- format: binary\n"); err != nil {
+		t.Fatalf("SetYAML() error = %v", err)
+	}
+	if _, err := doc.Delete(nil, "extra"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got, want := string(doc.Bytes()), "archives:\n  - format: binary\n"; got != want {
+		t.Errorf("document =\n%s\nwant\n%s", got, want)
+	}
+}